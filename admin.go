@@ -0,0 +1,215 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const adminMaxBodySize = 1024 * 1024
+
+// AdminOpt is the information of the option returned by AdminHandler.
+type AdminOpt struct {
+	Name    string      `json:"name"`
+	Short   string      `json:"short,omitempty"`
+	Aliases []string    `json:"aliases,omitempty"`
+	Type    string      `json:"type"`
+	Help    string      `json:"help,omitempty"`
+	IsCli   bool        `json:"cli"`
+	IsSet   bool        `json:"set"`
+	Value   interface{} `json:"value"`
+	Default interface{} `json:"default"`
+	Source  *OptSource  `json:"source,omitempty"`
+}
+
+// AdminHandler is a http handler to read and update the options at runtime,
+// which has the routes as follow:
+//
+//	GET    /opts         Return all the options as []AdminOpt.
+//	GET    /opts/{name}  Return the option named name as AdminOpt.
+//	PUT    /opts/{name}  Set the option value, and return the updated AdminOpt.
+//	DELETE /opts/{name}  Remove the runtime value set by PUT, and return the updated AdminOpt.
+//
+// The body of the PUT request is the raw option value, such as "8080" or
+// "1s", which is parsed and validated by the option like Config.Set.
+// After DELETE, the option falls back to the value of the next layer,
+// such as the file or env source, or the default value.
+// If the header "Content-Type" is "application/json", the body is decoded
+// as the json value, such as "[1, 2]" or "\"a,b\"".
+//
+// If the option does not exist, it responds "404 Not Found". If failing to
+// parse or validate the value, it responds "400 Bad Request". The error is
+// responded as the json like {"error": "..."}.
+type AdminHandler struct {
+	// Config is the configuration to be managed.
+	//
+	// Default: Conf
+	Config *Config
+
+	// If ReadOnly is true, PUT and DELETE are forbidden.
+	ReadOnly bool
+
+	// Authorize is used to authorize the request. If returning an error,
+	// it responds "403 Forbidden" with the error.
+	//
+	// Default: nil, which allows all the requests.
+	Authorize func(r *http.Request) error
+}
+
+// NewAdminHandler returns a new admin handler to manage the options of c.
+func (c *Config) NewAdminHandler() *AdminHandler {
+	return &AdminHandler{Config: c}
+}
+
+func (h *AdminHandler) config() *Config {
+	if h.Config == nil {
+		return Conf
+	}
+	return h.Config
+}
+
+// ServeHTTP implements the interface http.Handler.
+func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.Authorize != nil {
+		if err := h.Authorize(r); err != nil {
+			writeAdminError(w, http.StatusForbidden, err)
+			return
+		}
+	}
+
+	path := strings.TrimSuffix(r.URL.Path, "/")
+	switch {
+	case path == "/opts":
+		if r.Method != http.MethodGet {
+			writeAdminMethodNotAllowed(w, "GET")
+			return
+		}
+
+		c := h.config()
+		opts := c.GetAllOpts()
+		results := make([]AdminOpt, len(opts))
+		for i, opt := range opts {
+			results[i] = c.getAdminOpt(opt)
+		}
+		writeAdminJSON(w, http.StatusOK, results)
+
+	case strings.HasPrefix(path, "/opts/"):
+		name := strings.TrimPrefix(path, "/opts/")
+		h.serveOpt(w, r, strings.Replace(name, "/", h.config().gsep, -1))
+
+	default:
+		writeAdminError(w, http.StatusNotFound, fmt.Errorf("no route for '%s'", r.URL.Path))
+	}
+}
+
+func (h *AdminHandler) serveOpt(w http.ResponseWriter, r *http.Request, name string) {
+	c := h.config()
+	opt, ok := c.GetOpt(name)
+	if !ok {
+		writeAdminError(w, http.StatusNotFound, fmt.Errorf("no option named '%s'", name))
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+
+	case http.MethodPut, http.MethodDelete:
+		if h.ReadOnly {
+			writeAdminError(w, http.StatusForbidden, fmt.Errorf("the options are read-only"))
+			return
+		}
+
+		if r.Method == http.MethodDelete {
+			// Only remove the runtime value, so that the option falls back
+			// to the value of the next layer, such as the file or env source.
+			if option, ok := c.getOption(opt.Name); ok {
+				c.removeLayerValue(LayerRuntime.Name, option)
+			}
+			break
+		}
+
+		value, err := readAdminValue(r)
+		if err != nil {
+			writeAdminError(w, http.StatusBadRequest, err)
+			return
+		} else if value == nil {
+			writeAdminError(w, http.StatusBadRequest, fmt.Errorf("the option value must not be null"))
+			return
+		} else if err := c.Set(opt.Name, value); err != nil {
+			writeAdminError(w, http.StatusBadRequest, err)
+			return
+		}
+
+	default:
+		writeAdminMethodNotAllowed(w, "GET, PUT, DELETE")
+		return
+	}
+
+	writeAdminJSON(w, http.StatusOK, c.getAdminOpt(opt))
+}
+
+func (c *Config) getAdminOpt(opt Opt) (result AdminOpt) {
+	result = AdminOpt{
+		Name:    opt.Name,
+		Short:   opt.Short,
+		Aliases: opt.Aliases,
+		Type:    fmt.Sprintf("%T", opt.Default),
+		Help:    opt.Help,
+		IsCli:   opt.IsCli,
+		IsSet:   c.OptIsSet(opt.Name),
+		Value:   toEncodedValue(c.Get(opt.Name)),
+		Default: toEncodedValue(opt.Default),
+	}
+
+	if source, ok := c.GetOptSource(opt.Name); ok {
+		result.Source = &source
+	}
+	return
+}
+
+func readAdminValue(r *http.Request) (value interface{}, err error) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, adminMaxBodySize))
+	if err != nil {
+		return
+	}
+
+	if getMediaType(r.Header) == "application/json" {
+		if err = json.Unmarshal(data, &value); err != nil {
+			err = fmt.Errorf("invalid json value: %w", err)
+		}
+		return
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func writeAdminMethodNotAllowed(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	writeAdminError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed"))
+}
+
+func writeAdminError(w http.ResponseWriter, code int, err error) {
+	writeAdminJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+func writeAdminJSON(w http.ResponseWriter, code int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAdminHandler(t *testing.T) {
+	conf := New()
+	conf.RegisterOpts(IntOpt("port", "The port.").D(80).V(NewPortValidator()))
+	conf.Group("db").RegisterOpts(StrSliceOpt("addrs", "").D([]string{"a"}))
+
+	handler := conf.NewAdminHandler()
+	do := func(method, path, ct, body string) (code int, opt AdminOpt, errmsg string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if ct != "" {
+			req.Header.Set("Content-Type", ct)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code == http.StatusOK {
+			if err := json.Unmarshal(rec.Body.Bytes(), &opt); err != nil {
+				t.Fatal(err)
+			}
+		} else {
+			var resp struct{ Error string }
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			errmsg = resp.Error
+		}
+		return rec.Code, opt, errmsg
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/opts", nil))
+	var opts []AdminOpt
+	if err := json.Unmarshal(rec.Body.Bytes(), &opts); err != nil {
+		t.Fatal(err)
+	} else if len(opts) != 2 || opts[0].Name != "db.addrs" || opts[1].Name != "port" {
+		t.Errorf("unexpected options: %+v", opts)
+	} else if opts[1].Help != "The port." || opts[1].Type != "int" || opts[1].IsSet {
+		t.Errorf("unexpected option: %+v", opts[1])
+	}
+
+	if code, opt, _ := do(http.MethodPut, "/opts/port", "", "8080\n"); code != http.StatusOK {
+		t.Errorf("expect the status code %d, but got %d", http.StatusOK, code)
+	} else if !opt.IsSet || opt.Value != float64(8080) || opt.Default != float64(80) ||
+		opt.Source == nil || opt.Source.Source != "runtime" {
+		t.Errorf("unexpected option: %+v", opt)
+	} else if port := conf.GetInt("port"); port != 8080 {
+		t.Errorf("expect the port %d, but got %d", 8080, port)
+	}
+
+	if code, opt, _ := do(http.MethodPut, "/opts/db/addrs", "application/json", `["b","c"]`); code != http.StatusOK {
+		t.Errorf("expect the status code %d, but got %d", http.StatusOK, code)
+	} else if addrs := conf.GetStringSlice("db.addrs"); len(addrs) != 2 || addrs[0] != "b" || addrs[1] != "c" {
+		t.Errorf("unexpected addrs %v", addrs)
+	} else if opt.Name != "db.addrs" {
+		t.Errorf("unexpected option: %+v", opt)
+	}
+
+	if code, _, msg := do(http.MethodPut, "/opts/port", "", "65536"); code != http.StatusBadRequest {
+		t.Errorf("expect the status code %d, but got %d", http.StatusBadRequest, code)
+	} else if msg == "" {
+		t.Errorf("expect an error message")
+	}
+
+	if code, _, _ := do(http.MethodGet, "/opts/noopt", "", ""); code != http.StatusNotFound {
+		t.Errorf("expect the status code %d, but got %d", http.StatusNotFound, code)
+	}
+
+	if code, opt, _ := do(http.MethodDelete, "/opts/port", "", ""); code != http.StatusOK {
+		t.Errorf("expect the status code %d, but got %d", http.StatusOK, code)
+	} else if opt.Value != float64(80) {
+		t.Errorf("unexpected option: %+v", opt)
+	}
+
+	// DELETE only removes the runtime value and falls back to the lower layer.
+	_ = conf.LoadDataSet(DataSet{Source: "file:a", Format: "json", Data: []byte(`{"port": 8080}`)})
+	_ = conf.Set("port", 9090)
+	if code, opt, _ := do(http.MethodDelete, "/opts/port", "", ""); code != http.StatusOK {
+		t.Errorf("expect the status code %d, but got %d", http.StatusOK, code)
+	} else if opt.Value != float64(8080) || !opt.IsSet || opt.Source == nil || opt.Source.Layer != "file:a" {
+		t.Errorf("unexpected option: %+v", opt)
+	}
+
+	handler.ReadOnly = true
+	if code, _, _ := do(http.MethodPut, "/opts/port", "", "8080"); code != http.StatusForbidden {
+		t.Errorf("expect the status code %d, but got %d", http.StatusForbidden, code)
+	} else if code, _, _ = do(http.MethodGet, "/opts/port", "", ""); code != http.StatusOK {
+		t.Errorf("expect the status code %d, but got %d", http.StatusOK, code)
+	}
+
+	handler.Authorize = func(r *http.Request) error {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			return errors.New("invalid token")
+		}
+		return nil
+	}
+	if code, _, msg := do(http.MethodGet, "/opts/port", "", ""); code != http.StatusForbidden {
+		t.Errorf("expect the status code %d, but got %d", http.StatusForbidden, code)
+	} else if msg != "invalid token" {
+		t.Errorf("unexpected error message '%s'", msg)
+	}
+}
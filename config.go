@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -35,32 +36,174 @@ var VersionOpt = StrOpt("version", "Print the version and exit.").S("v").D("1.0.
 var Conf = New()
 
 type option struct {
-	value atomic.Value
-	opt   Opt
+	value  atomic.Pointer[optValue] // The effective value, which is nil if unset.
+	layers map[string]layerValue    // Protected by Config.llock.
+	opt    atomic.Pointer[Opt]      // Copy-on-write, which must not be modified.
+}
+
+func newOption(opt Opt) *option {
+	o := &option{}
+	o.opt.Store(&opt)
+	return o
+}
+
+// Opt returns the option information.
+func (o *option) Opt() Opt { return *o.opt.Load() }
+
+// updateOpt updates a copy of the option information by update,
+// then replaces the option information with it.
+func (o *option) updateOpt(update func(opt Opt) Opt) {
+	for {
+		old := o.opt.Load()
+		new := update(*old)
+		if o.opt.CompareAndSwap(old, &new) {
+			return
+		}
+	}
+}
+
+type optValue struct {
+	value  interface{}
+	source OptSource
+}
+
+type layerValue struct {
+	optValue
+	priority int
+	seq      uint64
+}
+
+func (o *option) load() (v optValue, ok bool) {
+	if p := o.value.Load(); p != nil {
+		return *p, true
+	}
+	return
+}
+
+func (o *option) IsSet() bool {
+	return o.value.Load() != nil
 }
 
 func (o *option) GetValue() interface{} {
-	return o.value.Load()
+	v, _ := o.load()
+	return v.value
 }
 
 func (o *option) Get() (value interface{}) {
-	if value = o.value.Load(); value == nil {
-		value = o.opt.Default
+	if v, ok := o.load(); ok {
+		return v.value
+	}
+	return o.Opt().Default
+}
+
+// effective returns the value of the layer with the highest priority,
+// or nil if no layer has the value. For the layers with the same priority,
+// the later updated wins.
+func (o *option) effective() *optValue {
+	var top *layerValue
+	for name := range o.layers {
+		lv := o.layers[name]
+		if top == nil || lv.priority > top.priority ||
+			(lv.priority == top.priority && lv.seq > top.seq) {
+			top = &lv
+		}
 	}
-	return
+
+	if top == nil {
+		return nil
+	}
+	return &top.optValue
+}
+
+// optChange is the change of the effective value of the option.
+type optChange struct {
+	option *option
+	old    interface{}
+	new    interface{}
+	source OptSource
+	unset  bool // The option is changed from set to unset.
 }
 
-func (o *option) Set(c *Config, newvalue interface{}) {
-	oldvalue := o.value.Swap(newvalue)
-	if oldvalue == nil {
-		oldvalue = o.opt.Default
+// OptSource represents the source which sets the current value of the option.
+type OptSource struct {
+	// Source is the source of the DataSet, such as "flag", "env",
+	// "file:/path/to/file", etc. Or "runtime" for Config.Set,
+	// and "map" for Config.LoadMap.
+	Source string `json:"source"`
+
+	// Layer is the name of the layer where the value is, such as "runtime".
+	Layer string `json:"layer,omitempty"`
+
+	// Checksum is the checksum of the DataSet, which may be empty.
+	Checksum string `json:"checksum,omitempty"`
+
+	// Timestamp is the timestamp of the DataSet if set,
+	// or the time when the option is set.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+func newOptSource(source, checksum string, timestamp time.Time) OptSource {
+	if timestamp.IsZero() {
+		timestamp = time.Now()
 	}
-	c.observe(o, oldvalue, newvalue)
+	return OptSource{Source: source, Checksum: checksum, Timestamp: timestamp}
 }
 
 // Observer is used to observe the change of the option value.
 type Observer func(optName string, oldValue, newValue interface{})
 
+// SourceObserver is the same as Observer, but also receives
+// the source which sets the new value.
+type SourceObserver func(optName string, oldValue, newValue interface{}, source OptSource)
+
+// OptChange represents the change of the value of an option.
+type OptChange struct {
+	Name   string      `json:"name"`
+	Old    interface{} `json:"old"`
+	New    interface{} `json:"new"`
+	Source OptSource   `json:"source"`
+}
+
+// ChangeSet is a set of the changes of the options applied together,
+// such as by LoadMap or LoadDataSet, which is sorted by the option name.
+type ChangeSet []OptChange
+
+// newChangeSet converts the changes, which have been sorted by the option name.
+func newChangeSet(changes []optChange) ChangeSet {
+	cs := make(ChangeSet, len(changes))
+	for i, change := range changes {
+		cs[i] = OptChange{
+			Name:   change.option.Opt().Name,
+			Old:    change.old,
+			New:    change.new,
+			Source: change.source,
+		}
+	}
+	return cs
+}
+
+// Names returns the names of all the changed options.
+func (cs ChangeSet) Names() []string {
+	names := make([]string, len(cs))
+	for i, change := range cs {
+		names[i] = change.Name
+	}
+	return names
+}
+
+// Get returns the change of the option named name.
+func (cs ChangeSet) Get(name string) (change OptChange, ok bool) {
+	i := sort.Search(len(cs), func(i int) bool { return cs[i].Name >= name })
+	if ok = i < len(cs) && cs[i].Name == name; ok {
+		change = cs[i]
+	}
+	return
+}
+
+// BatchObserver is used to observe the changes of the option values
+// applied together, which must not modify the change set.
+type BatchObserver func(changes ChangeSet)
+
 // Config is used to manage the configuration options.
 type Config struct {
 	// Args is the CLI rest arguments.
@@ -76,40 +219,60 @@ type Config struct {
 	// Default: log.Printf
 	Errorf func(format string, args ...interface{})
 
-	gen       uint64
-	gsep      string
-	ignore    bool
-	options   map[string]*option
-	aliases   map[string]string
-	daliases  map[string]string
-	decoders  map[string]Decoder
-	observers []Observer
-	exit      chan struct{}
+	gen    uint64
+	gsep   string
+	ignore bool
+	exit   chan struct{}
+
+	// reg is the copy-on-write registry, and rlock serializes the writers.
+	rlock sync.Mutex
+	reg   atomic.Pointer[registry]
+
+	// llock protects the layer values of all the options,
+	// and the queue of the changes to be notified.
+	llock  sync.Mutex
+	lseq   uint64
+	nqueue [][]optChange
+	nbusy  bool // Whether a goroutine is notifying the queued changes.
+
+	// notify is closed and renewed when any option value is changed.
+	nlock  sync.Mutex
+	notify chan struct{}
 }
 
-// New returns a new Config with the "json", "yaml/yml" and "ini" decoder.
+// New returns a new Config with the "json", "yaml/yml", "toml", "ini",
+// "properties" and "env" decoder, and the "json", "yaml/yml", "toml"
+// and "ini" encoder.
 func New() *Config {
 	c := &Config{
-		gsep:     ".",
-		ignore:   true,
-		options:  make(map[string]*option, 32),
-		aliases:  make(map[string]string, 8),
-		daliases: make(map[string]string, 4),
-		decoders: make(map[string]Decoder, 4),
-		exit:     make(chan struct{}),
+		gsep:   ".",
+		ignore: true,
+		exit:   make(chan struct{}),
 	}
+	c.reg.Store(newRegistry())
 
 	c.Version = VersionOpt
 	c.AddDecoder("ini", NewIniDecoder())
 	c.AddDecoder("json", NewJSONDecoder())
+	c.AddDecoder("yaml", NewYAMLDecoder())
+	c.AddDecoder("toml", NewTOMLDecoder())
+	c.AddDecoder("env", NewDotenvDecoder())
+	c.AddDecoder("properties", NewPropertiesDecoder())
 	c.AddDecoderTypeAliases("yaml", "yml")
+
+	c.AddEncoder("ini", NewIniEncoder())
+	c.AddEncoder("json", NewJSONEncoder())
+	c.AddEncoder("yaml", NewYAMLEncoder())
+	c.AddEncoder("toml", NewTOMLEncoder())
 	return c
 }
 
 // reset clears the whole config for test.
 func (c *Config) reset() {
-	c.options = make(map[string]*option)
-	c.aliases = make(map[string]string)
+	c.updateRegistry(func(r *registry) {
+		r.options = make(map[string]*option)
+		r.aliases = make(map[string]string)
+	})
 }
 
 // Stop stops the watchers of all the sources.
@@ -142,42 +305,110 @@ func (c *Config) errorf(format string, args ...interface{}) {
 // when updating the value of the option.
 func (c *Config) IgnoreNoOptError(ignore bool) { c.ignore = ignore }
 
-// Observe appends the observers to watch the change of all the option values.
-func (c *Config) Observe(observers ...Observer) {
-	c.observers = append(c.observers, observers...)
+// Observe appends the observers to watch the change of all the option values,
+// which are called once for each changed option in the order of the option
+// name when the options are changed together. Use ObserveBatch instead
+// to be notified once for all the options changed together.
+//
+// It returns a function to remove the observers.
+func (c *Config) Observe(observers ...Observer) (cancel func()) {
+	sobservers := make([]SourceObserver, len(observers))
+	for i, observer := range observers {
+		observe := observer
+		sobservers[i] = func(name string, old, new interface{}, _ OptSource) {
+			observe(name, old, new)
+		}
+	}
+	return c.ObserveWithSource(sobservers...)
+}
+
+// ObserveWithSource is the same as Observe, but the observers also receive
+// the source which sets the new value.
+func (c *Config) ObserveWithSource(observers ...SourceObserver) (cancel func()) {
+	_observers := make([]observer, len(observers))
+	for i, observe := range observers {
+		_observers[i] = observer{observe: observe}
+	}
+	return c.addObservers(_observers...)
+}
+
+// ObserveBatch appends the observers to watch the changes of all the option
+// values, which are called once with all the changed options after they are
+// applied together, such as by LoadMap, LoadDataSet, Set, etc.
+// The change sets are delivered in the order they are applied,
+// even if they are applied concurrently.
+//
+// It returns a function to remove the observers.
+func (c *Config) ObserveBatch(observers ...BatchObserver) (cancel func()) {
+	_observers := make([]observer, len(observers))
+	for i, observe := range observers {
+		_observers[i] = observer{batch: observe}
+	}
+	return c.addObservers(_observers...)
 }
 
-func (c *Config) observe(o *option, old, new interface{}) {
+func (c *Config) observe(o *option, old, new interface{}, source OptSource) {
 	if !reflect.DeepEqual(old, new) {
 		atomic.AddUint64(&c.gen, 1)
-		for _, observe := range c.observers {
-			observe(o.opt.Name, old, new)
+		c.notifyChanged()
+		opt := o.Opt()
+		for _, observer := range c.registry().observers {
+			if observer.observe != nil {
+				observer.observe(opt.Name, old, new, source)
+			}
 		}
-		if o.opt.OnUpdate != nil {
-			o.opt.OnUpdate(old, new)
+		if opt.OnUpdate != nil {
+			opt.OnUpdate(old, new)
 		}
 	}
 }
 
+// changed returns a channel which will be closed
+// when any option value is changed.
+func (c *Config) changed() <-chan struct{} {
+	c.nlock.Lock()
+	defer c.nlock.Unlock()
+	if c.notify == nil {
+		c.notify = make(chan struct{})
+	}
+	return c.notify
+}
+
+func (c *Config) notifyChanged() {
+	c.nlock.Lock()
+	if c.notify != nil {
+		close(c.notify)
+		c.notify = nil
+	}
+	c.nlock.Unlock()
+}
+
 func (c *Config) setOptAlias(old, new string) {
-	old = c.fixOptionName(old)
-	new = c.fixOptionName(new)
+	c.updateRegistry(func(r *registry) { r.setOptAlias(c.fixOptionName(old), c.fixOptionName(new)) })
+}
+
+func (r *registry) setOptAlias(old, new string) {
 	if old == "" || new == "" {
 		return
 	}
 
-	if opt, ok := c.options[new]; ok && !inString(old, opt.opt.Aliases) {
-		opt.opt.Aliases = append(opt.opt.Aliases, old)
+	if opt, ok := r.options[new]; ok {
+		opt.updateOpt(func(opt Opt) Opt {
+			if !inString(old, opt.Aliases) {
+				opt.Aliases = append(opt.Aliases[:len(opt.Aliases):len(opt.Aliases)], old)
+			}
+			return opt
+		})
 	}
 
-	c.aliases[old] = new
+	r.aliases[old] = new
 }
 
-func (c *Config) unsetOptAlias(name string) {
-	delete(c.aliases, name)
-	for oldname, newname := range c.aliases {
+func (r *registry) unsetOptAlias(name string) {
+	delete(r.aliases, name)
+	for oldname, newname := range r.aliases {
 		if newname == name {
-			delete(c.aliases, oldname)
+			delete(r.aliases, oldname)
 		}
 	}
 }
@@ -190,21 +421,26 @@ func (c *Config) registerOpt(opt Opt) (o *option) {
 	}
 
 	name := c.fixOptionName(opt.Name)
-	if _, ok := c.options[name]; ok {
-		panic(fmt.Errorf("the option named '%s' has been registered", name))
-	}
+	c.updateRegistry(func(r *registry) {
+		if _, ok := r.options[name]; ok {
+			panic(fmt.Errorf("the option named '%s' has been registered", name))
+		}
 
-	for _, alias := range opt.Aliases {
-		c.setOptAlias(alias, opt.Name)
-	}
+		for _, alias := range opt.Aliases {
+			r.setOptAlias(c.fixOptionName(alias), name)
+		}
 
-	o = &option{opt: opt}
-	c.options[name] = o
+		o = newOption(opt)
+		r.options[name] = o
+	})
 	return
 }
 
 // RegisterOpts registers a set of options.
 //
+// It is safe to register the options concurrently, even if the watchers
+// have been started.
+//
 // Notice: if a certain option has existed, it will panic.
 func (c *Config) RegisterOpts(opts ...Opt) {
 	names := make([]string, len(opts))
@@ -217,69 +453,59 @@ func (c *Config) RegisterOpts(opts ...Opt) {
 		names[i] = c.fixOptionName(opt.Name)
 	}
 
-	for _, name := range names {
-		if _, ok := c.options[name]; ok {
-			panic(fmt.Errorf("the option named '%s' has been registered", name))
+	c.updateRegistry(func(r *registry) {
+		for _, name := range names {
+			if _, ok := r.options[name]; ok {
+				panic(fmt.Errorf("the option named '%s' has been registered", name))
+			}
 		}
-	}
 
-	for i, opt := range opts {
-		for _, alias := range opt.Aliases {
-			c.setOptAlias(alias, opt.Name)
+		for i, opt := range opts {
+			for _, alias := range opt.Aliases {
+				r.setOptAlias(c.fixOptionName(alias), names[i])
+			}
+			r.options[names[i]] = newOption(opt)
 		}
-		c.options[names[i]] = &option{opt: opt}
-	}
+	})
 }
 
 // UnregisterOpts unregisters the registered options.
 func (c *Config) UnregisterOpts(optNames ...string) {
-	for _, name := range optNames {
-		c.unregisterOpt(name)
-	}
+	c.updateRegistry(func(r *registry) {
+		for _, name := range optNames {
+			name = c.fixOptionName(name)
+			delete(r.options, name)
+			r.unsetOptAlias(name)
+		}
+	})
 }
 
-func (c *Config) unregisterOpt(name string) {
-	name = c.fixOptionName(name)
-	delete(c.options, name)
-	c.unsetOptAlias(name)
+// getOption returns the option by the name or the alias.
+func (c *Config) getOption(name string) (opt *option, ok bool) {
+	return c.registry().getOption(c.fixOptionName(name))
 }
 
 // OptIsSet reports whether the option named name is set.
 //
 // Return false if the option does not exist.
 func (c *Config) OptIsSet(name string) (yes bool) {
-	name = c.fixOptionName(name)
-	if opt, ok := c.options[name]; ok {
-		yes = opt.value.Load() != nil
-	} else if name, ok = c.aliases[name]; ok {
-		if opt, ok = c.options[name]; ok {
-			yes = opt.value.Load() != nil
-		}
+	if opt, ok := c.getOption(name); ok {
+		yes = opt.IsSet()
 	}
 	return
 }
 
 // HasOpt reports whether the option named name has been registered.
 func (c *Config) HasOpt(name string) (yes bool) {
-	name = c.fixOptionName(name)
-	if _, yes = c.options[name]; !yes {
-		if alias, ok := c.aliases[name]; ok {
-			_, yes = c.options[alias]
-		}
-	}
+	_, yes = c.getOption(name)
 	return
 }
 
 // GetOpt returns the registered option by the name.
 func (c *Config) GetOpt(name string) (opt Opt, ok bool) {
-	name = c.fixOptionName(name)
-	option, ok := c.options[name]
+	option, ok := c.getOption(name)
 	if ok {
-		opt = option.opt
-	} else if name, ok = c.aliases[name]; ok {
-		if option, ok = c.options[name]; ok {
-			opt = option.opt
-		}
+		opt = option.Opt()
 	}
 	return
 }
@@ -287,10 +513,11 @@ func (c *Config) GetOpt(name string) (opt Opt, ok bool) {
 // GetAllOpts returns all the registered options.
 func (c *Config) GetAllOpts() []Opt { return c.getOpts(func(Opt) bool { return true }) }
 func (c *Config) getOpts(filter func(Opt) bool) []Opt {
-	opts := make([]Opt, 0, len(c.options))
-	for _, opt := range c.options {
-		if filter(opt.opt) {
-			opts = append(opts, opt.opt)
+	options := c.registry().options
+	opts := make([]Opt, 0, len(options))
+	for _, option := range options {
+		if opt := option.Opt(); filter(opt) {
+			opts = append(opts, opt)
 		}
 	}
 	sort.Sort(optsT(opts))
@@ -304,17 +531,14 @@ func (c *Config) updateOpt(name string, value interface{}, set bool) (
 	}
 
 	// Get the option by the name.
-	opt, ok := c.options[name]
+	opt, ok := c.registry().getOption(name)
 	if !ok {
-		if alias, ok := c.aliases[name]; !ok {
-			return nil, nil, ErrNoOpt
-		} else if opt, ok = c.options[alias]; !ok {
-			return nil, nil, ErrNoOpt
-		}
+		return nil, nil, ErrNoOpt
 	}
 
 	// Parse the option value
-	newvalue, err := opt.opt.Parser(value)
+	info := opt.Opt()
+	newvalue, err := info.Parser(value)
 	if err != nil {
 		return nil, nil, err
 	} else if newvalue == nil {
@@ -322,12 +546,13 @@ func (c *Config) updateOpt(name string, value interface{}, set bool) (
 	}
 
 	// Validate the option value
-	if err = opt.opt.validate(newvalue); err != nil {
+	if err = info.validate(newvalue); err != nil {
 		return nil, nil, err
 	}
 
 	if set {
-		opt.Set(c, newvalue)
+		values := map[*option]interface{}{opt: newvalue}
+		c.updateLayer(LayerRuntime, values, newOptSource("runtime", "", time.Time{}), false)
 	}
 
 	return opt, newvalue, nil
@@ -408,31 +633,32 @@ func (c *Config) flatMap(maps map[string]interface{}) map[string]interface{} {
 // and load all if failing to parse the value of any option.
 //
 // If force is missing or false, ignore the assigned options.
+//
+// The option values are loaded into the layer LayerRuntime.
 func (c *Config) LoadMap(options map[string]interface{}, force ...bool) error {
-	if len(options) == 0 {
-		return nil
-	}
-
 	var _force bool
 	if len(force) > 0 {
 		_force = force[0]
 	}
+	return c.loadMap(options, _force, newOptSource("map", "", time.Time{}))
+}
 
-	type opt struct {
-		name   string
-		value  interface{}
-		option *option
+func (c *Config) loadMap(options map[string]interface{}, _force bool, source OptSource) error {
+	if len(options) == 0 {
+		return nil
 	}
 
 	options = c.flatMap(options)
-	opts := make([]opt, 0, len(options))
+	values := make(map[*option]interface{}, len(options))
 
 	for name, value := range options {
 		name = c.fixOptionName(name)
 		o, newv, err := c.updateOpt(name, value, false)
 		switch err {
 		case nil:
-			if o.value.Load() != nil && !_force {
+			if o == nil { // The value is nil, such as "key:" in yaml.
+				continue
+			} else if o.IsSet() && !_force {
 				continue
 			}
 		case ErrNoOpt:
@@ -443,13 +669,10 @@ func (c *Config) LoadMap(options map[string]interface{}, force ...bool) error {
 		default:
 			return err
 		}
-		opts = append(opts, opt{name: name, value: newv, option: o})
-	}
-
-	for _, opt := range opts {
-		opt.option.Set(c, opt.value)
+		values[o] = newv
 	}
 
+	c.updateLayer(LayerRuntime, values, source, false)
 	return nil
 }
 
@@ -460,7 +683,8 @@ func (c *Config) Parse(name string, value interface{}) (interface{}, error) {
 	return value, err
 }
 
-// Set is used to reset the option named name to value.
+// Set is used to reset the option named name to value,
+// which is set into the layer LayerRuntime.
 func (c *Config) Set(name string, value interface{}) (err error) {
 	name = c.fixOptionName(name)
 	_, _, err = c.updateOpt(name, value, true)
@@ -470,17 +694,35 @@ func (c *Config) Set(name string, value interface{}) (err error) {
 	return
 }
 
+// Unset removes the value of the option named name from all the layers,
+// so that it is restored to the default value and is not set any more.
+// If the value is changed, the observers and the callback OnUpdate
+// of the option are called with the default value as the new value.
+func (c *Config) Unset(name string) (err error) {
+	if opt, ok := c.getOption(name); ok {
+		c.unsetOpts([]*option{opt})
+	} else if !c.ignore {
+		err = ErrNoOpt
+	}
+	return
+}
+
+// ResetAll resets all the options to the default values, like Unset.
+func (c *Config) ResetAll() {
+	options := c.registry().options
+	opts := make([]*option, 0, len(options))
+	for _, opt := range options {
+		opts = append(opts, opt)
+	}
+	c.unsetOpts(opts)
+}
+
 // Get returns the value of the option named name.
 //
 // Return nil if this option does not exist.
 func (c *Config) Get(name string) (value interface{}) {
-	name = c.fixOptionName(name)
-	if opt, ok := c.options[name]; ok {
+	if opt, ok := c.getOption(name); ok {
 		value = opt.Get()
-	} else if alias, ok := c.aliases[name]; ok {
-		if opt, ok = c.options[alias]; ok {
-			value = opt.Get()
-		}
 	}
 	return
 }
@@ -15,8 +15,10 @@
 package gconf
 
 import (
+	"fmt"
 	"reflect"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 )
@@ -225,3 +227,183 @@ func TestConfig_Observe(t *testing.T) {
 		t.Errorf("unexpected changed opt: %+v", o)
 	}
 }
+
+func TestConfig_ObserveBatch(t *testing.T) {
+	c := New()
+	c.RegisterOpts(StrOpt("host", "").D("127.0.0.1"), IntOpt("port", "").D(80), StrOpt("name", ""))
+
+	var changesets []ChangeSet
+	cancel := c.ObserveBatch(func(changes ChangeSet) {
+		if host, port := c.GetString("host"), c.GetInt("port"); host != "1.2.3.4" || port != 8080 {
+			t.Errorf("the batch is not applied: host=%s, port=%d", host, port)
+		}
+		changesets = append(changesets, changes)
+	})
+
+	_ = c.LoadMap(map[string]interface{}{"port": 8080, "host": "1.2.3.4", "name": ""})
+	_ = c.LoadMap(map[string]interface{}{"port": 8080}, true) // No change
+	_ = c.Set("name", "abc")
+
+	if len(changesets) != 2 {
+		t.Fatalf("expect %d change sets, but got %d", 2, len(changesets))
+	}
+
+	if names := changesets[0].Names(); !reflect.DeepEqual(names, []string{"host", "port"}) {
+		t.Errorf("unexpected changed options: %v", names)
+	} else if change, ok := changesets[0].Get("port"); !ok {
+		t.Errorf("missing the change of the option '%s'", "port")
+	} else if change.Old != 80 || change.New != 8080 || change.Source.Source != "map" {
+		t.Errorf("unexpected the change: %+v", change)
+	} else if _, ok := changesets[0].Get("name"); ok {
+		t.Errorf("unexpected the change of the option '%s'", "name")
+	}
+
+	if names := changesets[1].Names(); !reflect.DeepEqual(names, []string{"name"}) {
+		t.Errorf("unexpected changed options: %v", names)
+	}
+
+	// The removed observer is not notified any more.
+	cancel()
+	_ = c.Set("name", "xyz")
+	if len(changesets) != 2 {
+		t.Errorf("expect %d change sets, but got %d", 2, len(changesets))
+	}
+}
+
+func TestConfig_ObserveOrder(t *testing.T) {
+	c := New()
+	ms := make(map[string]interface{}, 16)
+	for i := 0; i < 16; i++ {
+		name := fmt.Sprintf("opt%02d", i)
+		c.RegisterOpts(IntOpt(name, ""))
+		ms[name] = i + 1
+	}
+
+	var names []string
+	cancel := c.Observe(func(name string, old, new interface{}) { names = append(names, name) })
+	cancelBatch := c.ObserveBatch(func(changes ChangeSet) {
+		if !sort.StringsAreSorted(names) {
+			t.Errorf("the observed options are not sorted: %v", names)
+		} else if expect := changes.Names(); !reflect.DeepEqual(names, expect) {
+			t.Errorf("expect the observed options %v, but got %v", expect, names)
+		}
+	})
+
+	for i := 0; i < 8; i++ {
+		names = names[:0]
+		for name := range ms {
+			ms[name] = ms[name].(int) + 1
+		}
+		_ = c.LoadMap(ms, true)
+	}
+
+	// The removed observers are not notified any more.
+	cancel()
+	cancelBatch()
+	names = names[:0]
+	_ = c.Set("opt00", 0)
+	if len(names) != 0 {
+		t.Errorf("unexpected the observed options: %v", names)
+	}
+}
+
+func TestConfig_ObserveConcurrently(t *testing.T) {
+	c := New()
+	c.RegisterOpts(IntOpt("host", ""), IntOpt("port", ""))
+
+	var last, lastBatch interface{}
+	c.Observe(func(name string, old, new interface{}) {
+		if name == "port" {
+			last = new
+		}
+	})
+	c.ObserveBatch(func(changes ChangeSet) {
+		if change, ok := changes.Get("port"); ok {
+			lastBatch = change.New
+		}
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 1; j <= 500; j++ {
+				v := i*1000 + j
+				_ = c.LoadMap(map[string]interface{}{"host": v, "port": v}, true)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if port := c.Get("port"); last != port {
+		t.Errorf("expect the last observed port %v, but got %v", port, last)
+	} else if lastBatch != port {
+		t.Errorf("expect the last batch port %v, but got %v", port, lastBatch)
+	}
+}
+
+func TestConfig_Unset(t *testing.T) {
+	var updated []interface{}
+	onUpdate := func(old, new interface{}) { updated = append(updated, old, new) }
+
+	c := New()
+	c.RegisterOpts(IntOpt("port", "").D(80).U(onUpdate), StrOpt("addr", "").D("127.0.0.1"))
+	c.Group("db").RegisterOpts(StrOpt("driver", "").D("mysql"), IntOpt("conns", "").D(10))
+
+	var changes [][]interface{}
+	c.Observe(func(name string, old, new interface{}) {
+		changes = append(changes, []interface{}{name, old, new})
+	})
+
+	_ = c.Set("port", 8080)
+	_ = c.Set("addr", "127.0.0.1")
+	gen, _ := c.Snapshot()
+
+	if err := c.Unset("port"); err != nil {
+		t.Fatal(err)
+	} else if c.OptIsSet("port") {
+		t.Errorf("expect the option '%s' to be unset", "port")
+	} else if port := c.GetInt("port"); port != 80 {
+		t.Errorf("expect the port %d, but got %d", 80, port)
+	} else if !reflect.DeepEqual(updated, []interface{}{80, 8080, 8080, 80}) {
+		t.Errorf("unexpected OnUpdate calls: %v", updated)
+	} else if last := changes[len(changes)-1]; !reflect.DeepEqual(last, []interface{}{"port", 8080, 80}) {
+		t.Errorf("unexpected the last change: %v", last)
+	}
+
+	// The value is equal to the default, but the snapshot is changed.
+	_ = c.Unset("addr")
+	if newgen, snap := c.Snapshot(); newgen != gen+2 {
+		t.Errorf("expect the generation %d, but got %d", gen+2, newgen)
+	} else if len(snap) != 0 {
+		t.Errorf("expect the empty snapshot, but got %v", snap)
+	}
+
+	_ = c.Set("db.driver", "pgsql")
+	_ = c.Set("db.conns", 20)
+	_ = c.Set("port", 8080)
+	c.Group("db").Reset()
+	if c.OptIsSet("db.driver") || c.OptIsSet("db.conns") {
+		t.Errorf("expect the options of the group '%s' to be unset", "db")
+	} else if driver := c.GetString("db.driver"); driver != "mysql" {
+		t.Errorf("expect the driver '%s', but got '%s'", "mysql", driver)
+	} else if !c.OptIsSet("port") {
+		t.Errorf("expect the option '%s' to be set", "port")
+	}
+
+	// The group name containing the hyphen.
+	c.Group("my-db").RegisterOpts(StrOpt("host", "").D("a"))
+	_ = c.Set("my-db.host", "b")
+	c.Group("my-db").Reset()
+	if c.OptIsSet("my-db.host") {
+		t.Errorf("expect the option '%s' to be unset", "my-db.host")
+	} else if host := c.GetString("my-db.host"); host != "a" {
+		t.Errorf("expect the host '%s', but got '%s'", "a", host)
+	}
+
+	c.ResetAll()
+	if _, snap := c.Snapshot(); len(snap) != 0 {
+		t.Errorf("expect the empty snapshot, but got %v", snap)
+	}
+}
@@ -16,10 +16,14 @@ package gconf
 
 import (
 	"bytes"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"unicode"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
 )
 
 // Decoder is used to decode the configuration data.
@@ -32,18 +36,21 @@ func AddDecoder(_type string, decoder Decoder) {
 
 // AddDecoder adds a decoder, which will override it if it has been added.s
 func (c *Config) AddDecoder(_type string, decoder Decoder) {
-	c.decoders[strings.ToLower(_type)] = decoder
+	c.updateRegistry(func(r *registry) {
+		r.decoders[strings.ToLower(_type)] = decoder
+	})
 }
 
 // GetDecoder returns the decoder by the type.
 //
 // Return nil if the decoder does not exist.
 func (c *Config) GetDecoder(_type string) (decoder Decoder) {
+	r := c.registry()
 	_type = strings.ToLower(_type)
-	decoder, ok := c.decoders[_type]
+	decoder, ok := r.decoders[_type]
 	if !ok {
-		if alias, ok := c.daliases[_type]; ok {
-			decoder = c.decoders[alias]
+		if alias, ok := r.daliases[_type]; ok {
+			decoder = r.decoders[alias]
 		}
 	}
 	return
@@ -59,58 +66,166 @@ func (c *Config) GetDecoder(_type string) (decoder Decoder) {
 // return the "yaml" decoder.
 func (c *Config) AddDecoderTypeAliases(_type string, aliases ...string) {
 	_type = strings.ToLower(_type)
-	for _, alias := range aliases {
-		c.daliases[strings.ToLower(alias)] = _type
-	}
+	c.updateRegistry(func(r *registry) {
+		for _, alias := range aliases {
+			r.daliases[strings.ToLower(alias)] = _type
+		}
+	})
 }
 
 // NewJSONDecoder returns a json decoder to decode the json data.
 //
-// If the json data contains the comment line starting with "//", it will remove
-// the comment line and parse the json data.
+// Besides the standard json, it also supports the JSONC and JSON5 extensions:
+//  1. The line comment starting with "//" and the block comment "/* ... */".
+//  2. The trailing comma after the last element of the object or array.
+//  3. The unquoted identifier as the object key.
+//  4. The single-quoted string.
+//  5. The hexadecimal number, the leading or trailing decimal point,
+//     the explicit plus sign, Infinity and NaN.
+//
+// The top-level value must be an object, or null which is decoded as
+// the empty object. If the data is invalid, it returns a *JSONSyntaxError
+// with the line and column number.
 func NewJSONDecoder() Decoder {
-	comment := []byte("//")
-	newline := []byte("\n")
+	return decodeJSON5
+}
+
+// NewYAMLDecoder returns a yaml decoder to decode the yaml data.
+//
+// Notice:
+//  1. The anchors, aliases and merge keys "<<" are supported.
+//  2. The data may contain many documents separated by "---", which are
+//     merged in turn, that's, the later document overrides the former.
+//  3. The sequence is decoded as []interface{}, which can be parsed
+//     by the slice options, such as StrSliceOpt, IntSliceOpt, etc.
+func NewYAMLDecoder() Decoder {
 	return func(src []byte, dst map[string]interface{}) (err error) {
-		if bytes.Contains(src, comment) {
-			buf := bytes.NewBuffer(nil)
-			buf.Grow(len(src))
-			for _, line := range bytes.Split(src, newline) {
-				if line = bytes.TrimSpace(line); len(line) == 0 {
-					buf.WriteByte('\n')
-					continue
-				} else if len(line) > 1 && line[0] == '/' && line[1] == '/' {
-					continue
+		decoder := yaml.NewDecoder(bytes.NewReader(src))
+		for {
+			var doc map[string]interface{}
+			if err = decoder.Decode(&doc); err != nil {
+				if errors.Is(err, io.EOF) {
+					return nil
 				}
-				buf.Write(line)
-				buf.WriteByte('\n')
+				return
+			}
+			mergeMap(dst, doc)
+		}
+	}
+}
+
+// NewTOMLDecoder returns a toml decoder to decode the toml data.
+//
+// Notice:
+//  1. The table is decoded as the nested map, which will be flattened
+//     as the option group by the group separator of Config.
+//  2. The array is decoded as []interface{} with the original element types,
+//     which can be parsed by the slice options, such as StrSliceOpt,
+//     IntSliceOpt, DurationSliceOpt, etc.
+func NewTOMLDecoder() Decoder {
+	return func(src []byte, dst map[string]interface{}) (err error) {
+		return toml.Unmarshal(src, &dst)
+	}
+}
+
+// mergeMap merges the map src into dst recursively.
+func mergeMap(dst, src map[string]interface{}) {
+	for key, value := range src {
+		if sm, ok := toStringMap(value); ok {
+			if dm, ok := toStringMap(dst[key]); ok {
+				mergeMap(dm, sm)
+				dst[key] = dm
+				continue
 			}
-			src = buf.Bytes()
 		}
-		return json.Unmarshal(src, &dst)
+		dst[key] = value
 	}
 }
 
-// NewIniDecoder returns a INI decoder to decode the INI data.
+func toStringMap(value interface{}) (map[string]interface{}, bool) {
+	switch vs := value.(type) {
+	case map[string]interface{}:
+		return vs, true
+	case map[interface{}]interface{}:
+		ms := make(map[string]interface{}, len(vs))
+		for k, v := range vs {
+			ms[fmt.Sprint(k)] = v
+		}
+		return ms, true
+	default:
+		return nil, false
+	}
+}
+
+// IniDecoderOptions is the options of the INI decoder.
+type IniDecoderOptions struct {
+	// DefaultGroup is the name of the default group, the options in which
+	// have no group prefix.
+	//
+	// Default: "DEFAULT"
+	DefaultGroup string
+
+	// InlineCommentChars is the set of the characters to start the inline
+	// comment after the value, which must be preceded by a spacewhite,
+	// such as "key = value ; comment".
+	//
+	// If empty, the inline comment is not supported.
+	InlineCommentChars string
+
+	// GroupSep is the separator of the nested groups in the section name,
+	// such as "[group1.group2]", which should be the same as the group
+	// separator of Config, see Config.GetGroupSep.
+	//
+	// Default: "."
+	GroupSep string
+}
+
+// NewIniDecoder returns a INI decoder to decode the INI data,
+// which supports the inline comment starting with ';' or '#'.
 //
 // Notice:
 //  1. The empty line will be ignored.
 //  2. The spacewhite on the beginning and end of line or value will be trimmed.
 //  3. The comment line starts with the character '#' or ';', which is ignored.
 //  4. The name of the default group is "DEFAULT", but it is optional.
-//  5. The group can nest other groups by ".", such as "group1.group2.group3".
+//  5. The group can nest other groups by the group separator ".", which is
+//     the same as Config.GetGroupSep(), such as "group1.group2.group3",
+//     which is decoded as the nested maps and flattened by the group separator
+//     of Config when loading it. It is an error that the group and the key
+//     in the same group have the same name, such as "[a.b]" and "b" in "[a]".
 //  6. The key must only contain the printable non-spacewhite characters.
 //  7. The line can continue to the next line with the last character "\",
 //     and the spacewhite on the beginning and end of the each line will be
 //     trimmed, then combines them with a space.
+//  8. The value may be quoted by the double quotes, which supports the escape
+//     sequences, such as "\"", "\\", "\n", "\t", "\r", or by the single
+//     quotes, which is used literally.
+//  9. The key with the suffix "[]" or occurring repeatedly in the same group
+//     is decoded as []string, such as "key[] = v1" and "key[] = v2",
+//     and "key[] =" without the value is decoded as the empty []string.
 func NewIniDecoder(defaultGroupName ...string) Decoder {
+	opts := IniDecoderOptions{InlineCommentChars: ";#"}
+	if len(defaultGroupName) > 0 {
+		opts.DefaultGroup = defaultGroupName[0]
+	}
+	return NewIniDecoderWithOptions(opts)
+}
+
+// NewIniDecoderWithOptions is the same as NewIniDecoder, but uses
+// the given options.
+func NewIniDecoderWithOptions(opts IniDecoderOptions) Decoder {
 	defaultGroup := "DEFAULT"
-	if len(defaultGroupName) > 0 && defaultGroupName[0] != "" {
-		defaultGroup = defaultGroupName[0]
+	if opts.DefaultGroup != "" {
+		defaultGroup = opts.DefaultGroup
+	}
+
+	groupSep := "."
+	if opts.GroupSep != "" {
+		groupSep = opts.GroupSep
 	}
 
 	return func(src []byte, dst map[string]interface{}) (err error) {
-		var gname string
+		group := dst
 		lines := strings.Split(string(src), "\n")
 		for index, maxIndex := 0, len(lines); index < maxIndex; {
 			line := strings.TrimSpace(lines[index])
@@ -122,10 +237,16 @@ func NewIniDecoder(defaultGroupName ...string) Decoder {
 			}
 
 			// Start a new group
-			if last := len(line) - 1; line[0] == '[' && line[last] == ']' {
-				gname = strings.TrimSpace(line[1:last])
-				if gname == defaultGroup {
-					gname = ""
+			if line[0] == '[' {
+				if line = stripIniInlineComment(line, opts.InlineCommentChars); line[len(line)-1] != ']' {
+					return fmt.Errorf("the %dth line misses the character ']'", index)
+				}
+
+				gname := strings.TrimSpace(line[1 : len(line)-1])
+				if gname == "" || gname == defaultGroup {
+					group = dst
+				} else if group, err = getIniGroup(dst, strings.Split(gname, groupSep)); err != nil {
+					return fmt.Errorf("the %dth line has an invalid group: %s", index, err)
 				}
 				continue
 			}
@@ -137,6 +258,11 @@ func NewIniDecoder(defaultGroupName ...string) Decoder {
 
 			// Get the key
 			key := strings.TrimSpace(line[:n])
+			isSlice := strings.HasSuffix(key, "[]")
+			if isSlice {
+				key = strings.TrimSpace(key[:len(key)-2])
+			}
+
 			if len(key) == 0 {
 				return fmt.Errorf("empty identifier key")
 			}
@@ -148,18 +274,20 @@ func NewIniDecoder(defaultGroupName ...string) Decoder {
 
 			// Get the value
 			value := strings.TrimSpace(line[n+1:])
-			if value == "" { // Ignore the empty value
-				continue
+			if value != "" && (value[0] == '"' || value[0] == '\'') {
+				if value, err = unquoteIniValue(value, opts.InlineCommentChars); err != nil {
+					return fmt.Errorf("the %dth line has an invalid value: %s", index, err)
+				}
+			} else if value = stripIniInlineComment(value, opts.InlineCommentChars); value == "" {
+				if _, ok := group[key]; !ok && isSlice {
+					group[key] = []string{}
+				}
+				continue // Ignore the empty value
 			} else if _len := len(value) - 1; value[_len] == '\\' { // The continuation line
 				vs := []string{strings.TrimSpace(strings.TrimRight(value, "\\"))}
 				for index < maxIndex {
-					value = strings.TrimSpace(lines[index])
-
-					var goon bool
-					if _len := len(value) - 1; value[_len] == '\\' {
-						goon = true
-					}
-
+					value = stripIniInlineComment(strings.TrimSpace(lines[index]), opts.InlineCommentChars)
+					goon := strings.HasSuffix(value, "\\")
 					if value = strings.TrimSpace(strings.TrimRight(value, "\\")); value == "" {
 						break
 					}
@@ -174,11 +302,365 @@ func NewIniDecoder(defaultGroupName ...string) Decoder {
 			}
 
 			// Add the option
-			if gname != "" {
-				key = strings.Join([]string{gname, key}, ".")
+			switch v := group[key].(type) {
+			case map[string]interface{}:
+				return fmt.Errorf("the %dth line has the key '%s' conflicting with the group", index, key)
+			case []string:
+				group[key] = append(v, value)
+			case string:
+				group[key] = []string{v, value}
+			default:
+				if isSlice {
+					group[key] = []string{value}
+				} else {
+					group[key] = value
+				}
+			}
+		}
+		return
+	}
+}
+
+func getIniGroup(dst map[string]interface{}, names []string) (map[string]interface{}, error) {
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		switch group := dst[name].(type) {
+		case nil:
+			_group := make(map[string]interface{}, 8)
+			dst[name] = _group
+			dst = _group
+		case map[string]interface{}:
+			dst = group
+		default:
+			return nil, fmt.Errorf("the group '%s' conflicts with the key", name)
+		}
+	}
+	return dst, nil
+}
+
+// stripIniInlineComment removes the inline comment starting with any of chars,
+// which must be preceded by a spacewhite.
+func stripIniInlineComment(value, chars string) string {
+	if chars == "" {
+		return value
+	}
+
+	for i := 1; i < len(value); i++ {
+		if strings.IndexByte(chars, value[i]) > -1 && (value[i-1] == ' ' || value[i-1] == '\t') {
+			return strings.TrimSpace(value[:i])
+		}
+	}
+	return value
+}
+
+func unquoteIniValue(value, commentChars string) (string, error) {
+	quote := value[0]
+
+	var b strings.Builder
+	b.Grow(len(value))
+	for i := 1; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == quote:
+			if rest := strings.TrimSpace(value[i+1:]); rest != "" &&
+				strings.IndexByte(commentChars, rest[0]) < 0 {
+				return "", fmt.Errorf("unexpected '%s' after the quoted value", rest)
+			}
+			return b.String(), nil
+
+		case c == '\\' && quote == '"' && i+1 < len(value):
+			i++
+			switch value[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(value[i])
+			}
+
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", fmt.Errorf("missing the closing quote")
+}
+
+// NewPropertiesDecoder returns a decoder to decode the data
+// of the Java-style properties.
+//
+// Notice:
+//  1. The empty line will be ignored.
+//  2. The comment line starts with the character '#' or '!', which is ignored.
+//  3. The key and value are separated by the first unescaped '=', ':'
+//     or spacewhite, such as "key=value", "key: value" or "key value".
+//  4. The escape sequences, such as "\t", "\n", "\r", "\f", "\uXXXX",
+//     are supported, and "\" followed by any other character is that character.
+//  5. The line can continue to the next line with the last unescaped
+//     character "\", and the leading spacewhite of the next line is trimmed.
+func NewPropertiesDecoder() Decoder {
+	return func(src []byte, dst map[string]interface{}) (err error) {
+		lines := strings.Split(string(src), "\n")
+		for index, maxIndex := 0, len(lines); index < maxIndex; {
+			lineno := index + 1
+			line := strings.TrimLeftFunc(strings.TrimRight(lines[index], "\r"), unicode.IsSpace)
+			index++
+
+			// Ignore the empty line and the comment line
+			if len(line) == 0 || line[0] == '#' || line[0] == '!' {
+				continue
+			}
+
+			// Join the continuation lines.
+			for isContinuationLine(line) && index < maxIndex {
+				next := strings.TrimRight(lines[index], "\r")
+				line = line[:len(line)-1] + strings.TrimLeftFunc(next, unicode.IsSpace)
+				index++
+			}
+			if isContinuationLine(line) {
+				line = line[:len(line)-1]
+			}
+
+			key, value := splitPropertiesLine(line)
+			if key, err = unescapeProperties(key); err != nil {
+				return fmt.Errorf("the %dth line has an invalid key: %s", lineno, err)
+			} else if key == "" {
+				return fmt.Errorf("the %dth line has an empty key", lineno)
+			}
+
+			if value, err = unescapeProperties(value); err != nil {
+				return fmt.Errorf("the %dth line has an invalid value: %s", lineno, err)
 			}
+
 			dst[key] = value
 		}
 		return
 	}
 }
+
+// isContinuationLine reports whether the line ends with an odd number of "\".
+func isContinuationLine(line string) bool {
+	var n int
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+func splitPropertiesLine(line string) (key, value string) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\':
+			i++
+		case c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f':
+			end = i
+			i = len(line)
+		}
+	}
+
+	key, value = line[:end], strings.TrimLeft(line[end:], " \t\f")
+	if len(value) > 0 && (value[0] == '=' || value[0] == ':') {
+		value = strings.TrimLeft(value[1:], " \t\f")
+	}
+	return
+}
+
+func unescapeProperties(s string) (string, error) {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", fmt.Errorf("malformed \\uXXXX encoding in '%s'", s)
+			}
+
+			var r rune
+			for _, c := range s[i+1 : i+5] {
+				switch {
+				case '0' <= c && c <= '9':
+					r = r<<4 | (c - '0')
+				case 'a' <= c && c <= 'f':
+					r = r<<4 | (c - 'a' + 10)
+				case 'A' <= c && c <= 'F':
+					r = r<<4 | (c - 'A' + 10)
+				default:
+					return "", fmt.Errorf("malformed \\uXXXX encoding in '%s'", s)
+				}
+			}
+			b.WriteRune(r)
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// NewDotenvDecoder returns a decoder to decode the data of the dotenv file,
+// such as ".env" used by docker-compose.
+//
+// Like NewEnvSource, the key will be converted to lower case and all the
+// underlines("_") will be converted to the dots("."). If giving the prefix,
+// it only uses the key matching the given prefix, then removes the prefix
+// and the rest is used as the option name.
+//
+// Notice:
+//  1. The empty line will be ignored.
+//  2. The comment line starts with the character '#', which is ignored.
+//  3. The line may start with "export ", which is ignored.
+//  4. The empty unquoted value is ignored.
+//  5. The value in the single quotes is used literally.
+//  6. The value in the double quotes supports the escape sequences, such as
+//     "\n", "\t", "\"", "\\", and may span multiple lines.
+//  7. The unquoted value is trimmed, and the inline comment starting with
+//     " #" is removed. It can continue to the next line with the last
+//     character "\".
+func NewDotenvDecoder(prefix ...string) Decoder {
+	var _prefix string
+	if len(prefix) > 0 {
+		if _prefix = strings.Trim(prefix[0], "_"); _prefix != "" {
+			_prefix = strings.ToLower(_prefix) + "_"
+		}
+	}
+
+	return func(src []byte, dst map[string]interface{}) (err error) {
+		lines := strings.Split(strings.Replace(string(src), "\r\n", "\n", -1), "\n")
+		for index, maxIndex := 0, len(lines); index < maxIndex; {
+			lineno := index + 1
+			line := strings.TrimSpace(lines[index])
+			index++
+
+			// Ignore the empty line and the comment line
+			if len(line) == 0 || line[0] == '#' {
+				continue
+			}
+
+			if strings.HasPrefix(line, "export ") || strings.HasPrefix(line, "export\t") {
+				line = strings.TrimSpace(line[len("export"):])
+			}
+
+			n := strings.IndexByte(line, '=')
+			if n < 0 {
+				return fmt.Errorf("the %dth line misses the separator '='", lineno)
+			}
+
+			key := strings.TrimSpace(line[:n])
+			if key == "" {
+				return fmt.Errorf("the %dth line has an empty key", lineno)
+			}
+			for _, r := range key {
+				if unicode.IsSpace(r) || !unicode.IsPrint(r) {
+					return fmt.Errorf("invalid identifier key '%s'", key)
+				}
+			}
+
+			var value string
+			switch value = strings.TrimSpace(line[n+1:]); {
+			case value == "": // Ignore the empty value
+				continue
+
+			case value[0] == '\'':
+				end := strings.IndexByte(value[1:], '\'')
+				if end < 0 {
+					return fmt.Errorf("the %dth line misses the closing quote", lineno)
+				}
+				value = value[1 : end+1]
+
+			case value[0] == '"':
+				value = value[1:]
+				for {
+					s, ok := unquoteDotenvValue(value)
+					if ok {
+						value = s
+						break
+					} else if index >= maxIndex {
+						return fmt.Errorf("the %dth line misses the closing quote", lineno)
+					}
+					value += "\n" + lines[index]
+					index++
+				}
+
+			default:
+				for strings.HasSuffix(value, "\\") && index < maxIndex {
+					value = strings.TrimSpace(value[:len(value)-1]) + " " +
+						strings.TrimSpace(lines[index])
+					index++
+				}
+				if n := strings.Index(value, " #"); n > -1 {
+					value = value[:n]
+				}
+				value = strings.TrimSpace(strings.TrimSuffix(value, "\\"))
+			}
+
+			key = strings.ToLower(key)
+			if _prefix != "" {
+				if !strings.HasPrefix(key, _prefix) {
+					continue
+				}
+				key = strings.TrimPrefix(key, _prefix)
+			}
+
+			if key = strings.Replace(strings.Trim(key, "_"), "_", ".", -1); key != "" {
+				dst[key] = value
+			}
+		}
+		return
+	}
+}
+
+// unquoteDotenvValue unescapes the double-quoted value s, the opening quote
+// of which has been removed, and reports whether the closing quote is found.
+func unquoteDotenvValue(s string) (value string, ok bool) {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			return b.String(), true
+
+		case '\\':
+			if i+1 == len(s) {
+				b.WriteByte(c)
+				continue
+			}
+
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(s[i])
+			}
+
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,504 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// JSONSyntaxError represents a syntax error of the JSONC/JSON5 data.
+type JSONSyntaxError struct {
+	Line   int // The line number, starting with 1.
+	Column int // The column number of the character, starting with 1.
+	Msg    string
+}
+
+// Error implements the interface error.
+func (e *JSONSyntaxError) Error() string {
+	return fmt.Sprintf("json: syntax error at line %d, column %d: %s",
+		e.Line, e.Column, e.Msg)
+}
+
+// decodeJSON5 decodes the JSONC/JSON5 data src, which must be an object,
+// into dst. The top-level null is decoded as the empty object.
+func decodeJSON5(src []byte, dst map[string]interface{}) error {
+	p := json5Parser{src: src}
+	p.skipSpaces()
+	if p.err != nil {
+		return p.err
+	} else if p.eof() {
+		return p.errorf("unexpected end of input")
+	} else if p.peek() == '{' {
+		p.parseObject(dst)
+	} else if start := p.pos; p.parseIdentifier() != "null" {
+		return p.errorAt(start, "the top-level value must be an object or null")
+	}
+
+	if p.err != nil {
+		return p.err
+	}
+
+	if p.skipSpaces(); p.err != nil {
+		return p.err
+	} else if !p.eof() {
+		return p.errorf("invalid character '%c' after top-level value", p.peek())
+	}
+	return nil
+}
+
+type json5Parser struct {
+	src []byte
+	pos int
+	err error
+}
+
+func (p *json5Parser) eof() bool  { return p.pos >= len(p.src) }
+func (p *json5Parser) peek() byte { return p.src[p.pos] }
+
+func (p *json5Parser) errorf(format string, args ...interface{}) error {
+	return p.errorAt(p.pos, format, args...)
+}
+
+func (p *json5Parser) errorAt(pos int, format string, args ...interface{}) error {
+	if p.err != nil {
+		return p.err
+	}
+
+	line, column := 1, 1
+	for i := 0; i < pos && i < len(p.src); {
+		r, n := utf8.DecodeRune(p.src[i:])
+		if r == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+		i += n
+	}
+
+	p.err = &JSONSyntaxError{Line: line, Column: column, Msg: fmt.Sprintf(format, args...)}
+	return p.err
+}
+
+// skipSpaces skips the whitespaces and the comments.
+func (p *json5Parser) skipSpaces() {
+	for !p.eof() {
+		switch c := p.peek(); c {
+		case ' ', '\t', '\r', '\n', '\f', '\v':
+			p.pos++
+
+		case '/':
+			if p.pos+1 >= len(p.src) {
+				p.errorf("invalid character '/'")
+				return
+			}
+
+			switch p.src[p.pos+1] {
+			case '/':
+				p.pos += 2
+				for !p.eof() && p.peek() != '\n' {
+					p.pos++
+				}
+
+			case '*':
+				start := p.pos
+				p.pos += 2
+				for {
+					if p.pos+1 >= len(p.src) {
+						p.errorAt(start, "unterminated block comment")
+						return
+					} else if p.src[p.pos] == '*' && p.src[p.pos+1] == '/' {
+						p.pos += 2
+						break
+					}
+					p.pos++
+				}
+
+			default:
+				p.errorf("invalid character '/'")
+				return
+			}
+
+		default:
+			if c < utf8.RuneSelf {
+				return
+			}
+
+			// Support the unicode whitespaces, such as U+00A0, U+FEFF, etc.
+			r, n := utf8.DecodeRune(p.src[p.pos:])
+			if !unicode.IsSpace(r) && r != '\uFEFF' {
+				return
+			}
+			p.pos += n
+		}
+	}
+}
+
+func (p *json5Parser) parseValue() (value interface{}) {
+	if p.skipSpaces(); p.err != nil {
+		return nil
+	} else if p.eof() {
+		p.errorf("unexpected end of input")
+		return nil
+	}
+
+	switch c := p.peek(); {
+	case c == '{':
+		ms := make(map[string]interface{})
+		p.parseObject(ms)
+		return ms
+
+	case c == '[':
+		return p.parseArray()
+
+	case c == '"' || c == '\'':
+		return p.parseString()
+
+	case c == '-' || c == '+' || c == '.' || ('0' <= c && c <= '9'):
+		return p.parseNumber()
+
+	default:
+		start := p.pos
+		switch ident := p.parseIdentifier(); ident {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		case "Infinity":
+			return math.Inf(1)
+		case "NaN":
+			return math.NaN()
+		case "":
+			p.errorf("invalid character '%c' looking for beginning of value", c)
+		default:
+			p.errorAt(start, "invalid literal '%s'", ident)
+		}
+		return nil
+	}
+}
+
+func (p *json5Parser) parseObject(ms map[string]interface{}) {
+	p.pos++ // Skip '{'
+	for {
+		if p.skipSpaces(); p.err != nil {
+			return
+		} else if p.eof() {
+			p.errorf("unexpected end of input, expect '}'")
+			return
+		} else if p.peek() == '}' {
+			p.pos++
+			return
+		}
+
+		// Parse the key.
+		var key string
+		switch c := p.peek(); c {
+		case '"', '\'':
+			key = p.parseString()
+		default:
+			if key = p.parseIdentifier(); key == "" {
+				p.errorf("invalid character '%c' looking for beginning of object key", c)
+			}
+		}
+		if p.err != nil {
+			return
+		}
+
+		if p.skipSpaces(); p.err != nil {
+			return
+		} else if p.eof() || p.peek() != ':' {
+			p.errorf("expect ':' after object key")
+			return
+		}
+		p.pos++
+
+		// Parse the value.
+		value := p.parseValue()
+		if p.err != nil {
+			return
+		}
+		ms[key] = value
+
+		if p.skipSpaces(); p.err != nil {
+			return
+		} else if p.eof() {
+			p.errorf("unexpected end of input, expect '}'")
+			return
+		}
+
+		switch c := p.peek(); c {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return
+		default:
+			p.errorf("invalid character '%c' after object key:value pair", c)
+			return
+		}
+	}
+}
+
+func (p *json5Parser) parseArray() (vs []interface{}) {
+	p.pos++ // Skip '['
+	vs = []interface{}{}
+	for {
+		if p.skipSpaces(); p.err != nil {
+			return
+		} else if p.eof() {
+			p.errorf("unexpected end of input, expect ']'")
+			return
+		} else if p.peek() == ']' {
+			p.pos++
+			return
+		}
+
+		value := p.parseValue()
+		if p.err != nil {
+			return
+		}
+		vs = append(vs, value)
+
+		if p.skipSpaces(); p.err != nil {
+			return
+		} else if p.eof() {
+			p.errorf("unexpected end of input, expect ']'")
+			return
+		}
+
+		switch c := p.peek(); c {
+		case ',':
+			p.pos++
+		case ']':
+			p.pos++
+			return
+		default:
+			p.errorf("invalid character '%c' after array element", c)
+			return
+		}
+	}
+}
+
+func isJSON5IdentifierRune(r rune, first bool) bool {
+	switch {
+	case r == '_' || r == '$' || unicode.IsLetter(r):
+		return true
+	case first:
+		return false
+	default:
+		return unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) ||
+			unicode.Is(unicode.Mc, r) || unicode.Is(unicode.Pc, r)
+	}
+}
+
+func (p *json5Parser) parseIdentifier() string {
+	start := p.pos
+	for !p.eof() {
+		r, n := utf8.DecodeRune(p.src[p.pos:])
+		if !isJSON5IdentifierRune(r, p.pos == start) {
+			break
+		}
+		p.pos += n
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *json5Parser) parseString() string {
+	quote := p.peek()
+	start := p.pos
+	p.pos++
+
+	var b strings.Builder
+	for {
+		if p.eof() {
+			p.errorAt(start, "unterminated string")
+			return ""
+		}
+
+		c := p.peek()
+		switch {
+		case c == quote:
+			p.pos++
+			return b.String()
+
+		case c == '\n' || c == '\r':
+			p.errorf("invalid newline in string")
+			return ""
+
+		case c == '\\':
+			p.pos++
+			if p.eof() {
+				p.errorAt(start, "unterminated string")
+				return ""
+			}
+
+			switch c = p.peek(); c {
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'v':
+				b.WriteByte('\v')
+			case '0':
+				b.WriteByte(0)
+			case '\n': // Line continuation
+			case '\r': // Line continuation
+				if p.pos+1 < len(p.src) && p.src[p.pos+1] == '\n' {
+					p.pos++
+				}
+			case 'x':
+				r, ok := p.parseHex(2)
+				if !ok {
+					return ""
+				}
+				b.WriteRune(r)
+				continue
+			case 'u':
+				r, ok := p.parseHex(4)
+				if !ok {
+					return ""
+				}
+
+				if utf16.IsSurrogate(r) && p.pos+1 < len(p.src) &&
+					p.src[p.pos] == '\\' && p.src[p.pos+1] == 'u' {
+					p.pos++
+					if r2, ok := p.parseHex(4); !ok {
+						return ""
+					} else if r = utf16.DecodeRune(r, r2); r == unicode.ReplacementChar {
+						b.WriteRune(unicode.ReplacementChar)
+						r = r2
+					}
+				}
+				b.WriteRune(r)
+				continue
+			default:
+				b.WriteByte(c)
+			}
+			p.pos++
+
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// parseHex parses the n hexadecimal digits after the current character
+// such as 'x' or 'u', and moves to the next character after them.
+func (p *json5Parser) parseHex(n int) (r rune, ok bool) {
+	start := p.pos + 1
+	end := start + n
+	if end > len(p.src) {
+		p.errorf("invalid escape sequence in string")
+		return
+	}
+
+	v, err := strconv.ParseUint(string(p.src[start:end]), 16, 32)
+	if err != nil {
+		p.errorf("invalid escape sequence in string")
+		return
+	}
+
+	p.pos = end
+	return rune(v), true
+}
+
+func (p *json5Parser) parseNumber() interface{} {
+	start := p.pos
+
+	sign := 1.0
+	switch p.peek() {
+	case '-':
+		sign = -1
+		fallthrough
+	case '+':
+		p.pos++
+	}
+
+	if p.eof() {
+		p.errorf("unexpected end of input in number")
+		return nil
+	}
+
+	switch c := p.peek(); {
+	case c == 'I':
+		if p.parseIdentifier() != "Infinity" {
+			p.errorAt(start, "invalid number")
+			return nil
+		}
+		return math.Inf(int(sign))
+
+	case c == 'N':
+		if p.parseIdentifier() != "NaN" {
+			p.errorAt(start, "invalid number")
+			return nil
+		}
+		return math.NaN()
+
+	case c == '0' && p.pos+1 < len(p.src) && (p.src[p.pos+1] == 'x' || p.src[p.pos+1] == 'X'):
+		p.pos += 2
+		digits := p.pos
+		for !p.eof() && isHexDigit(p.peek()) {
+			p.pos++
+		}
+
+		v, err := strconv.ParseUint(string(p.src[digits:p.pos]), 16, 64)
+		if err != nil {
+			p.errorAt(start, "invalid hexadecimal number '%s'", p.src[start:p.pos])
+			return nil
+		}
+		return sign * float64(v)
+
+	case c == '0' && p.pos+1 < len(p.src) && '0' <= p.src[p.pos+1] && p.src[p.pos+1] <= '9':
+		p.errorAt(start, "invalid number with the leading zero")
+		return nil
+	}
+
+	for !p.eof() {
+		c := p.peek()
+		if ('0' <= c && c <= '9') || c == '.' || c == 'e' || c == 'E' ||
+			((c == '+' || c == '-') && (p.src[p.pos-1] == 'e' || p.src[p.pos-1] == 'E')) {
+			p.pos++
+			continue
+		}
+		break
+	}
+
+	s := string(p.src[start:p.pos])
+	v, err := strconv.ParseFloat(strings.TrimPrefix(s, "+"), 64)
+	if err != nil {
+		p.errorAt(start, "invalid number '%s'", s)
+		return nil
+	}
+	return v
+}
+
+func isHexDigit(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
@@ -14,7 +14,12 @@
 
 package gconf
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
 
 func ExampleNewJSONDecoder() {
 	data := []byte(`{
@@ -45,3 +50,242 @@ func ExampleNewJSONDecoder() {
 	// 123
 	// map[home:http://www.example.com]
 }
+
+func ExampleNewJSONDecoder_json5() {
+	data := []byte(`{
+		/* The block comment,
+		   which spans multiple lines. */
+		name: 'Aaron', // The trailing comment.
+		url: "http://www.example.com/path", // "//" in the string.
+		hex: 0x10,
+		ratio: .5,
+		addrs: [
+			"1.2.3.4",
+			"5.6.7.8", // The trailing comma.
+		],
+	}`)
+
+	ms := make(map[string]interface{})
+	err := NewJSONDecoder()(data, ms)
+
+	fmt.Println(err)
+	fmt.Println(ms["name"])
+	fmt.Println(ms["url"])
+	fmt.Println(ms["hex"])
+	fmt.Println(ms["ratio"])
+	fmt.Println(ms["addrs"])
+
+	// Output:
+	// <nil>
+	// Aaron
+	// http://www.example.com/path
+	// 16
+	// 0.5
+	// [1.2.3.4 5.6.7.8]
+}
+
+func TestJSONDecoderSyntaxError(t *testing.T) {
+	data := []byte(`{
+	"name": "Aaron",
+	"age": 123 456,
+}`)
+
+	var serr *JSONSyntaxError
+	err := NewJSONDecoder()(data, make(map[string]interface{}))
+	if !errors.As(err, &serr) {
+		t.Fatalf("expect a JSONSyntaxError, but got '%v'", err)
+	} else if serr.Line != 3 || serr.Column != 13 {
+		t.Errorf("expect the error at line %d column %d, but got line %d column %d",
+			3, 13, serr.Line, serr.Column)
+	}
+
+	for _, data := range []string{`{"a": 007}`, `{"a": -01.5}`, `[1]`, `"abc"`, `nul`} {
+		if err := NewJSONDecoder()([]byte(data), make(map[string]interface{})); !errors.As(err, &serr) {
+			t.Errorf("%s: expect a JSONSyntaxError, but got '%v'", data, err)
+		}
+	}
+
+	for _, data := range []string{`null`, ` null // comment`, `{"a": 0, "b": -0.5, "c": 0e1}`} {
+		if err := NewJSONDecoder()([]byte(data), make(map[string]interface{})); err != nil {
+			t.Errorf("%s: unexpected error '%v'", data, err)
+		}
+	}
+
+	data = []byte(`{"name": "Aaron" /* unterminated comment }`)
+	err = NewJSONDecoder()(data, make(map[string]interface{}))
+	if !errors.As(err, &serr) {
+		t.Fatalf("expect a JSONSyntaxError, but got '%v'", err)
+	} else if serr.Line != 1 || serr.Column != 18 {
+		t.Errorf("expect the error at line %d column %d, but got line %d column %d",
+			1, 18, serr.Line, serr.Column)
+	}
+}
+
+func ExampleNewIniDecoder() {
+	data := []byte(`
+name = "Aaron \"Smith\"" ; inline comment
+path = 'C:\path\to'
+url = http://www.example.com/#anchor # inline comment
+
+[db.primary]
+addr = 127.0.0.1:3306
+tags[] = a
+tags[] = b
+hosts = host1
+hosts = host2
+`)
+
+	ms := make(map[string]interface{})
+	err := NewIniDecoder()(data, ms)
+
+	fmt.Println(err)
+	fmt.Println(ms["name"])
+	fmt.Println(ms["path"])
+	fmt.Println(ms["url"])
+	fmt.Println(ms["db"])
+
+	// Output:
+	// <nil>
+	// Aaron "Smith"
+	// C:\path\to
+	// http://www.example.com/#anchor
+	// map[primary:map[addr:127.0.0.1:3306 hosts:[host1 host2] tags:[a b]]]
+}
+
+func TestIniDecoderGroupConflict(t *testing.T) {
+	for _, data := range []string{
+		"[a.b]\nx = 1\n[a]\nb = 2",
+		"[a]\nb = 2\n[a.b]\nx = 1",
+	} {
+		if err := NewIniDecoder()([]byte(data), make(map[string]interface{})); err == nil {
+			t.Errorf("expect an error for the ini data %q, but got nil", data)
+		}
+	}
+
+	ms := make(map[string]interface{})
+	decode := NewIniDecoderWithOptions(IniDecoderOptions{GroupSep: "::"})
+	if err := decode([]byte("[a::b.c]\nx = 1"), ms); err != nil {
+		t.Fatal(err)
+	} else if expect := map[string]interface{}{"a": map[string]interface{}{
+		"b.c": map[string]interface{}{"x": "1"}}}; !reflect.DeepEqual(ms, expect) {
+		t.Errorf("expect '%v', but got '%v'", expect, ms)
+	}
+}
+
+func ExampleNewYAMLDecoder() {
+	data := []byte(`
+base: &base
+  host: 127.0.0.1
+  port: 80
+
+server:
+  <<: *base
+  port: 8080
+  addrs:
+    - 1.2.3.4
+    - 5.6.7.8
+---
+server:
+  host: localhost
+`)
+
+	ms := make(map[string]interface{})
+	err := NewYAMLDecoder()(data, ms)
+
+	fmt.Println(err)
+	fmt.Println(ms["base"])
+	fmt.Println(ms["server"])
+
+	// Output:
+	// <nil>
+	// map[host:127.0.0.1 port:80]
+	// map[addrs:[1.2.3.4 5.6.7.8] host:localhost port:8080]
+}
+
+func ExampleNewTOMLDecoder() {
+	data := []byte(`
+name = "Aaron"
+
+[server]
+port = 8080
+addrs = ["1.2.3.4", "5.6.7.8"]
+timeouts = ["1s", "2s"]
+
+[server.tls]
+enabled = true
+`)
+
+	ms := make(map[string]interface{})
+	err := NewTOMLDecoder()(data, ms)
+
+	fmt.Println(err)
+	fmt.Println(ms["name"])
+	fmt.Println(ms["server"])
+
+	// Output:
+	// <nil>
+	// Aaron
+	// map[addrs:[1.2.3.4 5.6.7.8] port:8080 timeouts:[1s 2s] tls:map[enabled:true]]
+}
+
+func ExampleNewPropertiesDecoder() {
+	data := []byte(`
+# comment
+! comment
+name = Aaron
+server.host: 127.0.0.1
+server.port 8080
+server.addrs = 1.2.3.4, \
+               5.6.7.8
+key\ with\ spaces = value\twith escapes
+`)
+
+	ms := make(map[string]interface{})
+	err := NewPropertiesDecoder()(data, ms)
+
+	fmt.Println(err)
+	fmt.Println(ms["name"])
+	fmt.Println(ms["server.host"])
+	fmt.Println(ms["server.port"])
+	fmt.Println(ms["server.addrs"])
+	fmt.Printf("%q\n", ms["key with spaces"])
+
+	// Output:
+	// <nil>
+	// Aaron
+	// 127.0.0.1
+	// 8080
+	// 1.2.3.4, 5.6.7.8
+	// "value\twith escapes"
+}
+
+func ExampleNewDotenvDecoder() {
+	data := []byte(`
+# comment
+export NAME=Aaron
+SERVER_HOST='127.0.0.1'
+SERVER_PORT=8080 # inline comment
+SERVER_ADDRS=1.2.3.4, \
+  5.6.7.8
+SERVER_BANNER="Hello \"World\"
+Welcome"
+`)
+
+	ms := make(map[string]interface{})
+	err := NewDotenvDecoder()(data, ms)
+
+	fmt.Println(err)
+	fmt.Println(ms["name"])
+	fmt.Println(ms["server.host"])
+	fmt.Println(ms["server.port"])
+	fmt.Println(ms["server.addrs"])
+	fmt.Printf("%q\n", ms["server.banner"])
+
+	// Output:
+	// <nil>
+	// Aaron
+	// 127.0.0.1
+	// 8080
+	// 1.2.3.4, 5.6.7.8
+	// "Hello \"World\"\nWelcome"
+}
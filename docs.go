@@ -0,0 +1,161 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+var docsHeaders = []string{"Name", "Short", "Aliases", "Type", "Default",
+	"CLI", "Env", "Validators", "Description"}
+
+// WriteDocs is equal to Conf.WriteDocs(w, format, envPrefix...).
+func WriteDocs(w io.Writer, format string, envPrefix ...string) error {
+	return Conf.WriteDocs(w, format, envPrefix...)
+}
+
+// WriteDocs writes the reference documents of all the registered options
+// into w, which contains a table per group.
+//
+// format supports "markdown" or "md", and "html".
+//
+// envPrefix is the prefix given to NewEnvSource, which is used to generate
+// the name of the environment variable matching the option.
+func (c *Config) WriteDocs(w io.Writer, format string, envPrefix ...string) (err error) {
+	var prefix string
+	if len(envPrefix) > 0 {
+		if prefix = strings.Trim(envPrefix[0], "_"); prefix != "" {
+			prefix += "_"
+		}
+	}
+
+	var write func(*bufio.Writer, string, [][]string)
+	switch strings.ToLower(format) {
+	case "markdown", "md":
+		write = writeMarkdownTable
+	case "html":
+		write = writeHTMLTable
+	default:
+		return fmt.Errorf("unsupported docs format '%s'", format)
+	}
+
+	buf := bufio.NewWriter(w)
+	c.writeDocsGroup(buf, c.getOptGroupTree(c.GetAllOpts()), prefix, write)
+	return buf.Flush()
+}
+
+func (c *Config) writeDocsGroup(w *bufio.Writer, tree *optGroupTree,
+	envPrefix string, write func(*bufio.Writer, string, [][]string)) {
+	if len(tree.opts) > 0 {
+		rows := make([][]string, len(tree.opts))
+		for i, opt := range tree.opts {
+			if tree.name != "" {
+				opt.Name = strings.Replace(tree.name, ".", c.gsep, -1) + c.gsep + opt.Name
+			}
+			rows[i] = c.getOptDocs(opt, envPrefix)
+		}
+		write(w, tree.name, rows)
+	}
+
+	for _, group := range tree.sortedGroups() {
+		c.writeDocsGroup(w, group, envPrefix, write)
+	}
+}
+
+func (c *Config) getOptDocs(opt Opt, envPrefix string) []string {
+	var short, cli string
+	if opt.Short != "" {
+		short = "-" + opt.Short
+	}
+	if opt.IsCli {
+		cli = "--" + strings.Replace(opt.Name, "_", "-", -1)
+	}
+
+	env := strings.Replace(c.fixOptionName(opt.Name), c.gsep, "_", -1)
+	env = strings.ToUpper(envPrefix + env)
+
+	var _default string
+	switch v := toEncodedValue(opt.Default).(type) {
+	case string:
+		_default = v
+	default:
+		_default = fmt.Sprint(v)
+	}
+
+	return []string{
+		opt.Name,
+		short,
+		strings.Join(opt.Aliases, ", "),
+		fmt.Sprintf("%T", opt.Default),
+		_default,
+		cli,
+		env,
+		strings.Join(opt.ValidatorDescs, "; "),
+		strings.TrimSpace(opt.Help),
+	}
+}
+
+func writeMarkdownTable(w *bufio.Writer, group string, rows [][]string) {
+	if group == "" {
+		w.WriteString("## Global Options\n\n")
+	} else {
+		fmt.Fprintf(w, "## Group `%s`\n\n", group)
+	}
+
+	w.WriteString("| " + strings.Join(docsHeaders, " | ") + " |\n")
+	w.WriteString(strings.Repeat("|---", len(docsHeaders)) + "|\n")
+	for _, row := range rows {
+		w.WriteByte('|')
+		for i, cell := range row {
+			cell = strings.Replace(cell, "|", `\|`, -1)
+			cell = strings.Replace(cell, "\n", "<br>", -1)
+			switch {
+			case cell == "":
+			case i == 0 || i == 5 || i == 6: // Name, CLI and Env
+				cell = "`" + cell + "`"
+			}
+			w.WriteString(" " + cell + " |")
+		}
+		w.WriteByte('\n')
+	}
+	w.WriteByte('\n')
+}
+
+func writeHTMLTable(w *bufio.Writer, group string, rows [][]string) {
+	if group == "" {
+		w.WriteString("<h2>Global Options</h2>\n")
+	} else {
+		fmt.Fprintf(w, "<h2>Group <code>%s</code></h2>\n", html.EscapeString(group))
+	}
+
+	w.WriteString("<table>\n<thead>\n<tr>")
+	for _, header := range docsHeaders {
+		fmt.Fprintf(w, "<th>%s</th>", header)
+	}
+	w.WriteString("</tr>\n</thead>\n<tbody>\n")
+	for _, row := range rows {
+		w.WriteString("<tr>")
+		for _, cell := range row {
+			cell = strings.Replace(html.EscapeString(cell), "\n", "<br>", -1)
+			fmt.Fprintf(w, "<td>%s</td>", cell)
+		}
+		w.WriteString("</tr>\n")
+	}
+	w.WriteString("</tbody>\n</table>\n\n")
+}
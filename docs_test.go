@@ -0,0 +1,72 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func ExampleConfig_WriteDocs() {
+	c := New()
+	c.RegisterOpts(StrOpt("name", "The name of the app.").D("demo").S("n").As("appname"))
+	c.Group("db").RegisterOpts(
+		IntOpt("port", "The port of db.").D(3306).DV(NewValidator("a port", NewPortValidator())).Cli(false),
+		StrOpt("addr", "The address of db.").DV(NewValidator("an empty string or an address like host:port", NewMaybeAddressValidator())),
+	)
+
+	_ = c.WriteDocs(os.Stdout, "markdown", "app")
+
+	// Output:
+	// ## Global Options
+	//
+	// | Name | Short | Aliases | Type | Default | CLI | Env | Validators | Description |
+	// |---|---|---|---|---|---|---|---|---|
+	// | `name` | -n | appname | string | demo | `--name` | `APP_NAME` |  | The name of the app. |
+	//
+	// ## Group `db`
+	//
+	// | Name | Short | Aliases | Type | Default | CLI | Env | Validators | Description |
+	// |---|---|---|---|---|---|---|---|---|
+	// | `db.addr` |  |  | string |  | `--db.addr` | `APP_DB_ADDR` | an empty string or an address like host:port | The address of db. |
+	// | `db.port` |  |  | int | 3306 |  | `APP_DB_PORT` | a port | The port of db. |
+	//
+}
+
+func TestConfigWriteDocsHTML(t *testing.T) {
+	c := New()
+	c.RegisterOpts(StrOpt("name", "<the name>").D("a").V(NewStrLenValidator(1, 8)))
+	c.RegisterStruct(&struct {
+		Mode string `default:"a" validate:"oneof=a|b"`
+	}{})
+
+	buf := bytes.NewBuffer(nil)
+	if err := c.WriteDocs(buf, "html"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{"<h2>Global Options</h2>", "<td>NAME</td>",
+		"<td>one of [a b]</td>", "<td>&lt;the name&gt;</td>"} {
+		if !strings.Contains(buf.String(), s) {
+			t.Errorf("missing '%s' in the html docs:\n%s", s, buf.String())
+		}
+	}
+
+	if err := c.WriteDocs(buf, "xml"); err == nil {
+		t.Errorf("expect an error for the unsupported format")
+	}
+}
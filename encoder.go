@@ -0,0 +1,255 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoEncoder represents the error that there is no encoder.
+var ErrNoEncoder = errors.New("no encoder")
+
+// Encoder is used to encode the configuration data, which is the inverse
+// of Decoder.
+//
+// The options in src have been nested by the group, that's, the option
+// "group1.group2.opt" is represented as
+//
+//	map[string]interface{} {
+//	    "group1": map[string]interface{} {
+//	        "group2": map[string]interface{} {
+//	            "opt": value,
+//	        },
+//	    },
+//	}
+type Encoder func(src map[string]interface{}) ([]byte, error)
+
+// AddEncoder is equal to Conf.AddEncoder(_type, encoder).
+func AddEncoder(_type string, encoder Encoder) {
+	Conf.AddEncoder(_type, encoder)
+}
+
+// AddEncoder adds an encoder, which will override it if it has been added.
+func (c *Config) AddEncoder(_type string, encoder Encoder) {
+	c.updateRegistry(func(r *registry) {
+		r.encoders[strings.ToLower(_type)] = encoder
+	})
+}
+
+// GetEncoder returns the encoder by the type, which also supports
+// the type aliases added by AddDecoderTypeAliases.
+//
+// Return nil if the encoder does not exist.
+func (c *Config) GetEncoder(_type string) (encoder Encoder) {
+	r := c.registry()
+	_type = strings.ToLower(_type)
+	encoder, ok := r.encoders[_type]
+	if !ok {
+		if alias, ok := r.daliases[_type]; ok {
+			encoder = r.encoders[alias]
+		}
+	}
+	return
+}
+
+// Encode is equal to Conf.Encode(format, includeDefaults).
+func Encode(format string, includeDefaults bool) ([]byte, error) {
+	return Conf.Encode(format, includeDefaults)
+}
+
+// Encode encodes the current values of all the options by the encoder
+// of the given format, which can be decoded by the decoder of the same format.
+//
+// If includeDefaults is false, only encode the options that have been set.
+func (c *Config) Encode(format string, includeDefaults bool) ([]byte, error) {
+	encoder := c.GetEncoder(format)
+	if encoder == nil {
+		return nil, ErrNoEncoder
+	}
+
+	ms := make(map[string]interface{}, 32)
+	for _, opt := range c.GetAllOpts() {
+		if includeDefaults || c.OptIsSet(opt.Name) {
+			c.setNestedValue(ms, opt.Name, toEncodedValue(c.Get(opt.Name)))
+		}
+	}
+	return encoder(ms)
+}
+
+// setNestedValue sets the value of the option named name into ms
+// by splitting the name with the group separator.
+//
+// If a group has the same name as an option, the rest of the option name
+// in the group is used as the key.
+func (c *Config) setNestedValue(ms map[string]interface{}, name string, value interface{}) {
+	for {
+		index := strings.Index(name, c.gsep)
+		if index < 0 {
+			break
+		}
+
+		group := name[:index]
+		v, exist := ms[group]
+		if !exist {
+			v = make(map[string]interface{}, 8)
+			ms[group] = v
+		}
+
+		sub, ok := v.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		ms = sub
+		name = name[index+len(c.gsep):]
+	}
+	ms[name] = value
+}
+
+// toEncodedValue converts the option value to the value
+// which can be encoded and parsed back by the option parser.
+func toEncodedValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Duration:
+		return v.String()
+
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+
+	case []time.Duration:
+		ss := make([]string, len(v))
+		for i, d := range v {
+			ss[i] = d.String()
+		}
+		return ss
+
+	case fmt.Stringer:
+		return v.String()
+
+	default:
+		return value
+	}
+}
+
+// NewJSONEncoder returns a json encoder to encode the data as the json.
+func NewJSONEncoder() Encoder {
+	return func(src map[string]interface{}) ([]byte, error) {
+		return json.MarshalIndent(src, "", "  ")
+	}
+}
+
+// NewYAMLEncoder returns a yaml encoder to encode the data as the yaml.
+func NewYAMLEncoder() Encoder {
+	return func(src map[string]interface{}) ([]byte, error) {
+		return yaml.Marshal(src)
+	}
+}
+
+// NewTOMLEncoder returns a toml encoder to encode the data as the toml,
+// which encodes the group as the table.
+func NewTOMLEncoder() Encoder {
+	return func(src map[string]interface{}) ([]byte, error) {
+		buf := bytes.NewBuffer(nil)
+		if err := toml.NewEncoder(buf).Encode(src); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+}
+
+// NewIniEncoder returns a INI encoder to encode the data as the INI,
+// which can be decoded by NewIniDecoder.
+//
+// The options without the group are encoded before any section, the group
+// is encoded as the section, and the nested group is encoded as the section
+// whose name is joined by ".", such as "[group1.group2]". The slice value
+// is encoded as the repeated keys with the suffix "[]", and the empty slice
+// is encoded as the key with the suffix "[]" but without the value.
+func NewIniEncoder() Encoder {
+	return func(src map[string]interface{}) ([]byte, error) {
+		buf := bytes.NewBuffer(nil)
+		writeIniSection(buf, "", src)
+		return buf.Bytes(), nil
+	}
+}
+
+func writeIniSection(buf *bytes.Buffer, section string, ms map[string]interface{}) {
+	keys := make([]string, 0, len(ms))
+	groups := make([]string, 0, 4)
+	for key, value := range ms {
+		if _, ok := value.(map[string]interface{}); ok {
+			groups = append(groups, key)
+		} else {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	sort.Strings(groups)
+
+	if section != "" && len(keys) > 0 {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintf(buf, "[%s]\n", section)
+	}
+
+	for _, key := range keys {
+		writeIniValue(buf, key, ms[key])
+	}
+
+	for _, group := range groups {
+		name := group
+		if section != "" {
+			name = section + "." + group
+		}
+		writeIniSection(buf, name, ms[group].(map[string]interface{}))
+	}
+}
+
+func writeIniValue(buf *bytes.Buffer, key string, value interface{}) {
+	switch vf := reflect.ValueOf(value); vf.Kind() {
+	case reflect.Slice, reflect.Array:
+		if _, ok := value.([]byte); !ok {
+			if vf.Len() == 0 { // It is decoded as the empty slice.
+				fmt.Fprintf(buf, "%s[] =\n", key)
+				return
+			}
+
+			for i, _len := 0, vf.Len(); i < _len; i++ {
+				fmt.Fprintf(buf, "%s[] = %s\n", key, quoteIniValue(fmt.Sprint(vf.Index(i).Interface())))
+			}
+			return
+		}
+	}
+	fmt.Fprintf(buf, "%s = %s\n", key, quoteIniValue(fmt.Sprint(value)))
+}
+
+func quoteIniValue(s string) string {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s, "\"';#\\\r\n\t") {
+		s = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`).Replace(s)
+		return `"` + s + `"`
+	}
+	return s
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newEncoderTestConfig() *Config {
+	c := New()
+	c.RegisterOpts(
+		StrOpt("name", "").D("Aaron"),
+		IntOpt("age", ""),
+		DurationOpt("timeout", ""),
+	)
+	c.Group("db").RegisterOpts(StrOpt("driver", "").D("mysql"))
+	c.Group("db.primary").RegisterOpts(
+		StrOpt("addr", ""),
+		StrSliceOpt("tags", ""),
+		DurationSliceOpt("retries", ""),
+	)
+	return c
+}
+
+func TestConfigEncode(t *testing.T) {
+	for _, format := range []string{"json", "yaml", "toml", "ini"} {
+		src := newEncoderTestConfig()
+		_ = src.Set("age", 18)
+		_ = src.Set("timeout", "3s")
+		_ = src.Set("db.primary.addr", "127.0.0.1:3306")
+		_ = src.Set("db.primary.tags", []string{"a;b", "c d"})
+		_ = src.Set("db.primary.retries", "1s,2s")
+
+		data, err := src.Encode(format, true)
+		if err != nil {
+			t.Errorf("%s: fail to encode: %s", format, err)
+			continue
+		}
+
+		dst := newEncoderTestConfig()
+		err = dst.LoadDataSet(DataSet{Data: data, Format: format})
+		if err != nil {
+			t.Errorf("%s: fail to decode: %s", format, err)
+			continue
+		}
+
+		for _, opt := range src.GetAllOpts() {
+			if expect, value := src.Get(opt.Name), dst.Get(opt.Name); !reflect.DeepEqual(expect, value) {
+				t.Errorf("%s: option '%s' expects '%v', but got '%v'", format, opt.Name, expect, value)
+			}
+		}
+
+		if !dst.OptIsSet("name") {
+			t.Errorf("%s: expect the default option 'name' to be encoded", format)
+		}
+	}
+}
+
+func TestConfigEncode_EmptySlice(t *testing.T) {
+	newConfig := func() *Config {
+		c := New()
+		c.RegisterOpts(StrSliceOpt("tags", "").D([]string{"a", "b"}), IntSliceOpt("ports", "").D([]int{80}))
+		return c
+	}
+
+	for _, format := range []string{"json", "yaml", "toml", "ini"} {
+		src := newConfig()
+		_ = src.Set("tags", []string{})
+		_ = src.Set("ports", []int{})
+
+		data, err := src.Encode(format, false)
+		if err != nil {
+			t.Errorf("%s: fail to encode: %s", format, err)
+			continue
+		}
+
+		dst := newConfig()
+		if err = dst.LoadDataSet(DataSet{Data: data, Format: format}); err != nil {
+			t.Errorf("%s: fail to decode: %s", format, err)
+			continue
+		}
+
+		if tags := dst.GetStringSlice("tags"); len(tags) != 0 || !dst.OptIsSet("tags") {
+			t.Errorf("%s: expect the empty tags, but got '%v'", format, tags)
+		}
+		if ports := dst.GetIntSlice("ports"); len(ports) != 0 || !dst.OptIsSet("ports") {
+			t.Errorf("%s: expect the empty ports, but got '%v'", format, ports)
+		}
+	}
+}
+
+func ExampleConfig_Encode() {
+	c := New()
+	c.RegisterOpts(StrOpt("name", "").D("Aaron"), DurationOpt("timeout", ""))
+	c.Group("db").RegisterOpts(StrOpt("addr", ""), StrSliceOpt("tags", ""))
+	_ = c.Set("timeout", time.Second)
+	_ = c.Set("db.addr", "127.0.0.1:3306")
+	_ = c.Set("db.tags", "a,b")
+
+	data, _ := c.Encode("ini", false)
+	fmt.Print(string(data))
+
+	// Output:
+	// timeout = 1s
+	//
+	// [db]
+	// addr = 127.0.0.1:3306
+	// tags[] = a
+	// tags[] = b
+}
@@ -38,6 +38,16 @@ func Snapshot() (generation uint64, snap map[string]interface{}) {
 	return Conf.Snapshot()
 }
 
+// SnapshotWithSources is equal to Conf.SnapshotWithSources().
+func SnapshotWithSources() (generation uint64, snap map[string]OptValue) {
+	return Conf.SnapshotWithSources()
+}
+
+// GetOptSource is equal to Conf.GetOptSource(name).
+func GetOptSource(name string) (source OptSource, ok bool) {
+	return Conf.GetOptSource(name)
+}
+
 // LoadMap is equal to Conf.LoadMap(options, force...).
 func LoadMap(options map[string]interface{}, force ...bool) error {
 	return Conf.LoadMap(options)
@@ -46,6 +56,12 @@ func LoadMap(options map[string]interface{}, force ...bool) error {
 // Set is equal to Conf.Set(name, value).
 func Set(name string, value interface{}) error { return Conf.Set(name, value) }
 
+// Unset is equal to Conf.Unset(name).
+func Unset(name string) error { return Conf.Unset(name) }
+
+// ResetAll is equal to Conf.ResetAll().
+func ResetAll() { Conf.ResetAll() }
+
 // Get is equal to Conf.Get(name).
 func Get(name string) interface{} { return Conf.Get(name) }
 
@@ -53,7 +69,15 @@ func Get(name string) interface{} { return Conf.Get(name) }
 func Must(name string) interface{} { return Conf.Must(name) }
 
 // Observe is equal to Conf.Observe(observers...).
-func Observe(observers ...Observer) { Conf.Observe(observers...) }
+func Observe(observers ...Observer) (cancel func()) { return Conf.Observe(observers...) }
+
+// ObserveWithSource is equal to Conf.ObserveWithSource(observers...).
+func ObserveWithSource(observers ...SourceObserver) (cancel func()) {
+	return Conf.ObserveWithSource(observers...)
+}
+
+// ObserveBatch is equal to Conf.ObserveBatch(observers...).
+func ObserveBatch(observers ...BatchObserver) (cancel func()) { return Conf.ObserveBatch(observers...) }
 
 // GetGroupSep is equal to Conf.GetGroupSep().
 func GetGroupSep() (sep string) { return Conf.GetGroupSep() }
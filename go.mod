@@ -1,5 +1,9 @@
 module github.com/xgfone/gconf/v6
 
-require github.com/xgfone/go-defaults v0.15.0
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/xgfone/go-defaults v0.15.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 go 1.21
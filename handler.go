@@ -0,0 +1,234 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errNoGroup = errors.New("no option group")
+
+const (
+	handlerMaxWait   = time.Minute * 5
+	handlerHeartbeat = time.Second * 30
+)
+
+// HTTPHandler is equal to Conf.HTTPHandler().
+func HTTPHandler() http.Handler { return Conf.HTTPHandler() }
+
+// HTTPHandler returns a http handler to serve the current values of all
+// the options as the nested json, which can be consumed by NewURLSource
+// in other processes, so that the process is the config authority.
+//
+// The request path is the group, such as "/db" or "/db/redis" for the group
+// "db.redis", which only serves the options in the group but still nested
+// under the group, so the response can be loaded directly. "/" serves all.
+// So mount it as a sub-path like this:
+//
+//	http.Handle("/config/", http.StripPrefix("/config", conf.HTTPHandler()))
+//
+// The response has the header "ETag", which is the md5 of the response body.
+// And it supports the watch protocols of the url source:
+//
+//  1. The conditional request with "If-None-Match", which responds
+//     "304 Not Modified" if the values are not changed.
+//  2. The long-polling request with "If-None-Match" and the query argument
+//     "wait" in seconds, which holds the request until the values of the group
+//     are changed or at most "wait" seconds (5m at most), then responds the
+//     new values or "304 Not Modified".
+//  3. The Server-Sent Events with "Accept: text/event-stream", which sends
+//     an event "config" with the id of the ETag each time the values of the
+//     group are changed. If "Last-Event-ID" is equal to the current ETag,
+//     the first event is not sent.
+func (c *Config) HTTPHandler() http.Handler {
+	return http.HandlerFunc(c.serveHTTP)
+}
+
+func (c *Config) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	group := strings.Trim(r.URL.Path, "/")
+	if group != "" {
+		group = strings.Replace(group, "/", c.gsep, -1) + c.gsep
+	}
+
+	changed := c.changed()
+	data, etag, err := c.encodeGroup(group)
+	if err != nil {
+		c.serveError(w, r, err)
+		return
+	}
+
+	if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		c.serveEvents(w, r, group, changed, data, etag)
+		return
+	}
+
+	if r.Header.Get("If-None-Match") == etag {
+		if wait := getWaitDuration(r); wait > 0 {
+			if data, etag, err = c.waitGroup(r, group, changed, etag, wait); err != nil {
+				c.serveError(w, r, err)
+				return
+			}
+		}
+	}
+
+	header := w.Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", "no-cache")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	header.Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write(data)
+	}
+}
+
+func (c *Config) serveError(w http.ResponseWriter, r *http.Request, err error) {
+	if err == errNoGroup {
+		http.NotFound(w, r)
+	} else {
+		c.errorf("fail to encode the options for '%s': %s", r.URL.Path, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func getWaitDuration(r *http.Request) time.Duration {
+	seconds, err := strconv.ParseUint(r.URL.Query().Get("wait"), 10, 32)
+	if err != nil {
+		return 0
+	} else if wait := time.Duration(seconds) * time.Second; wait < handlerMaxWait {
+		return wait
+	}
+	return handlerMaxWait
+}
+
+// waitGroup waits until the values of the group are changed, or timeout.
+func (c *Config) waitGroup(r *http.Request, group string, changed <-chan struct{},
+	etag string, wait time.Duration) (data []byte, newetag string, err error) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-changed:
+		case <-timer.C:
+			return nil, etag, nil
+		case <-r.Context().Done():
+			return nil, etag, nil
+		}
+
+		changed = c.changed()
+		if data, newetag, err = c.encodeGroup(group); err != nil || newetag != etag {
+			return
+		}
+	}
+}
+
+func (c *Config) serveEvents(w http.ResponseWriter, r *http.Request,
+	group string, changed <-chan struct{}, data []byte, etag string) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusNotAcceptable)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	if r.Header.Get("Last-Event-ID") != etag {
+		fmt.Fprintf(w, "id: %s\nevent: config\ndata: %s\n\n", etag, data)
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(handlerHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-changed:
+			changed = c.changed()
+			newdata, newetag, err := c.encodeGroup(group)
+			if err != nil {
+				c.errorf("fail to encode the options for '%s': %s", r.URL.Path, err)
+				continue
+			} else if newetag == etag {
+				continue
+			}
+
+			data, etag = newdata, newetag
+			fmt.Fprintf(w, "id: %s\nevent: config\ndata: %s\n\n", etag, data)
+
+		case <-ticker.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+
+		case <-r.Context().Done():
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// encodeGroup encodes the current values of the options in the group
+// as the nested json, and returns it with its ETag.
+//
+// The values are read together by loadValues, so that the data and its ETag
+// describe the consistent state which never mixes the values of two batches.
+func (c *Config) encodeGroup(group string) (data []byte, etag string, err error) {
+	var exist bool
+	ms := make(map[string]interface{}, 32)
+	opts := c.GetAllOpts()
+	c.loadValues(func() {
+		for _, opt := range opts {
+			if strings.HasPrefix(opt.Name, group) {
+				c.setNestedValue(ms, opt.Name, toEncodedValue(c.Get(opt.Name)))
+				exist = true
+			}
+		}
+	})
+
+	if !exist && group != "" {
+		return nil, "", errNoGroup
+	}
+
+	if data, err = json.Marshal(ms); err == nil {
+		sum := md5.Sum(data)
+		etag = `"` + hex.EncodeToString(sum[:]) + `"`
+	}
+	return
+}
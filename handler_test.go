@@ -0,0 +1,166 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newHandlerTestConfig() *Config {
+	c := New()
+	c.RegisterOpts(StrOpt("name", "").D("server"))
+	c.Group("db").RegisterOpts(StrOpt("addr", "").D("127.0.0.1:3306"), IntOpt("conns", "").D(10))
+	return c
+}
+
+func TestConfigHTTPHandler(t *testing.T) {
+	conf := newHandlerTestConfig()
+	server := httptest.NewServer(conf.HTTPHandler())
+	defer server.Close()
+
+	get := func(path, etag string) (*http.Response, string) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		data, _ := io.ReadAll(resp.Body)
+		return resp, string(data)
+	}
+
+	resp, data := get("/", "")
+	if expect := `{"db":{"addr":"127.0.0.1:3306","conns":10},"name":"server"}`; data != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, data)
+	}
+
+	resp, data = get("/db", "")
+	if expect := `{"db":{"addr":"127.0.0.1:3306","conns":10}}`; data != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, data)
+	}
+
+	etag := resp.Header.Get("ETag")
+	if resp, _ = get("/db", etag); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("expect the status code %d, but got %d", http.StatusNotModified, resp.StatusCode)
+	}
+
+	if resp, _ = get("/nogroup", ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expect the status code %d, but got %d", http.StatusNotFound, resp.StatusCode)
+	}
+
+	// Long-polling: the change of other groups does not wake up the request.
+	go func() {
+		time.Sleep(time.Millisecond * 100)
+		_ = conf.Set("name", "authority")
+		time.Sleep(time.Millisecond * 100)
+		_ = conf.Set("db.conns", 20)
+	}()
+
+	start := time.Now()
+	resp, data = get("/db?wait=10", etag)
+	if elapsed := time.Since(start); elapsed < time.Millisecond*200 || elapsed > time.Second*5 {
+		t.Errorf("unexpected the waiting duration '%s'", elapsed)
+	} else if expect := `{"db":{"addr":"127.0.0.1:3306","conns":20}}`; data != expect {
+		t.Errorf("expect '%s', but got '%s'", expect, data)
+	} else if resp.Header.Get("ETag") == etag {
+		t.Errorf("expect a new etag, but got the old")
+	}
+
+	etag = resp.Header.Get("ETag")
+	if resp, _ = get("/db?wait=1", etag); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("expect the status code %d, but got %d", http.StatusNotModified, resp.StatusCode)
+	}
+}
+
+func TestConfigHTTPHandlerWithURLSource(t *testing.T) {
+	for _, mode := range []URLWatchMode{URLWatchLongPoll, URLWatchSSE} {
+		authority := newHandlerTestConfig()
+		server := httptest.NewServer(http.StripPrefix("/config", authority.HTTPHandler()))
+
+		client := newHandlerTestConfig()
+		source := NewURLSourceWithOptions(server.URL+"/config/db", URLWatch(mode))
+		if err := client.LoadAndWatchSource(source); err != nil {
+			t.Fatalf("%s: %s", mode, err)
+		}
+
+		time.Sleep(time.Millisecond * 100)
+		_ = authority.Set("name", "authority")
+		_ = authority.Set("db.addr", "10.0.0.1:3306")
+
+		for start := time.Now(); time.Since(start) < time.Second*3; {
+			if client.GetString("db.addr") == "10.0.0.1:3306" {
+				break
+			}
+			time.Sleep(time.Millisecond * 10)
+		}
+
+		if addr := client.GetString("db.addr"); addr != "10.0.0.1:3306" {
+			t.Errorf("%s: expect the addr '%s', but got '%s'", mode, "10.0.0.1:3306", addr)
+		} else if name := client.GetString("name"); name != "server" {
+			t.Errorf("%s: expect the name '%s', but got '%s'", mode, "server", name)
+		}
+
+		client.Stop()
+		server.Close()
+	}
+}
+
+func TestConfigHTTPHandlerConsistency(t *testing.T) {
+	c := New()
+	c.RegisterOpts(IntOpt("opt1", ""), IntOpt("opt2", ""))
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				_ = c.LoadMap(map[string]interface{}{"opt1": i, "opt2": i}, true)
+			}
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		data, _, err := c.encodeGroup("")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ms map[string]int
+		if err = json.Unmarshal(data, &ms); err != nil {
+			t.Fatal(err)
+		} else if ms["opt1"] != ms["opt2"] {
+			t.Fatalf("inconsistent data: %s", data)
+		}
+	}
+
+	close(stop)
+	wg.Wait()
+}
@@ -0,0 +1,403 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// The priorities of the predefined layers. The default value of the option
+// is the lowest layer, which is used only if no other layer has the value.
+const (
+	PriorityFile    = 100
+	PriorityEnv     = 200
+	PriorityFlag    = 300
+	PriorityRuntime = 400
+)
+
+// The predefined layers.
+var (
+	LayerFile    = Layer{Name: "file", Priority: PriorityFile}
+	LayerEnv     = Layer{Name: "env", Priority: PriorityEnv}
+	LayerFlag    = Layer{Name: "flag", Priority: PriorityFlag}
+	LayerRuntime = Layer{Name: "runtime", Priority: PriorityRuntime}
+)
+
+// Layer is a named layer of the option values with a priority. The effective
+// value of the option is the value of the layer with the highest priority
+// which has it, and it falls back to the next layer if the value is removed
+// from the layer, or to the default value if no layer has it.
+//
+// For the layers with the same priority, the later updated wins.
+//
+// Each source occupies a layer with the different name, see SourceLayer.
+// Config.Set and Config.LoadMap always update the layer LayerRuntime.
+type Layer struct {
+	Name     string
+	Priority int
+}
+
+// SourceLayer returns the layer of the source named name, which is used by
+// Config.LoadDataSet, Config.LoadSource and Config.LoadAndWatchSource.
+//
+//	"env", "env:..."   => Layer{Name: name, Priority: PriorityEnv}
+//	"flag", "flag:..." => Layer{Name: name, Priority: PriorityFlag}
+//	others             => Layer{Name: name, Priority: PriorityFile}
+//
+// So each source, such as "file:/path/to/file" or "url:http://host/path",
+// occupies its own layer, and the layers of the env and flag sources
+// are LayerEnv and LayerFlag.
+func SourceLayer(name string) Layer {
+	switch kind, _, _ := strings.Cut(name, ":"); kind {
+	case LayerEnv.Name:
+		return Layer{Name: name, Priority: PriorityEnv}
+	case LayerFlag.Name:
+		return Layer{Name: name, Priority: PriorityFlag}
+	default:
+		return Layer{Name: name, Priority: PriorityFile}
+	}
+}
+
+// LoadMapLayer is equal to Conf.LoadMapLayer(layer, options, source).
+func LoadMapLayer(layer Layer, options map[string]interface{}, source OptSource) error {
+	return Conf.LoadMapLayer(layer, options, source)
+}
+
+// LoadMapLayer replaces all the option values in the layer with options,
+// that's, the options which are in the layer but not in options are removed
+// from the layer and fall back to the next layer.
+//
+// source is recorded as the source of the option values, the field Layer
+// of which is set to the layer name.
+//
+// If failing to parse or validate the value of any option,
+// it terminates and the layer is not changed.
+func (c *Config) LoadMapLayer(layer Layer, options map[string]interface{}, source OptSource) error {
+	if layer.Name == "" {
+		panic("the layer name must not be empty")
+	}
+
+	options = c.flatMap(options)
+	values := make(map[*option]interface{}, len(options))
+	for name, value := range options {
+		name = c.fixOptionName(name)
+		o, newv, err := c.updateOpt(name, value, false)
+		switch err {
+		case nil:
+			if o == nil { // The value is nil, such as "key:" in yaml.
+				continue
+			}
+		case ErrNoOpt:
+			if c.ignore {
+				continue
+			}
+			return fmt.Errorf("no option named '%s'", name)
+		default:
+			return err
+		}
+		values[o] = newv
+	}
+
+	source = newOptSource(source.Source, source.Checksum, source.Timestamp)
+	c.updateLayer(layer, values, source, true)
+	return nil
+}
+
+// LoadDataSetLayer is equal to Conf.LoadDataSetLayer(layer, ds).
+func LoadDataSetLayer(layer Layer, ds DataSet) error {
+	return Conf.LoadDataSetLayer(layer, ds)
+}
+
+// LoadDataSetLayer decodes the DataSet ds and replaces all the option values
+// in the layer with it, like LoadMapLayer.
+//
+// If the data of ds is empty, such as the file is truncated or removed,
+// all the option values in the layer are removed. But it does nothing
+// if ds.NotModified is true.
+//
+// If the arguments of ds are not nil, they are set into Config.Args
+// only if Config.Args is nil.
+func (c *Config) LoadDataSetLayer(layer Layer, ds DataSet) (err error) {
+	if err = c.loadDataSetLayer(layer, ds); err == nil && ds.Args != nil && c.Args == nil {
+		c.Args = ds.Args
+	}
+	return
+}
+
+func (c *Config) loadDataSetLayer(layer Layer, ds DataSet) (err error) {
+	if ds.NotModified {
+		return nil
+	}
+
+	ms, err := c.decodeDataSet(ds)
+	if err != nil {
+		return err
+	}
+
+	source := OptSource{Source: ds.Source, Checksum: ds.Checksum, Timestamp: ds.Timestamp}
+	return c.LoadMapLayer(layer, ms, source)
+}
+
+// LoadSourceLayer is equal to Conf.LoadSourceLayer(layer, source).
+func LoadSourceLayer(layer Layer, source Source) error {
+	return Conf.LoadSourceLayer(layer, source)
+}
+
+// LoadSourceLayer reads the data from the source and loads it into the layer.
+func (c *Config) LoadSourceLayer(layer Layer, source Source) (err error) {
+	_, err = c.loadSource(c.bindSource(source), func(ds DataSet) error {
+		return c.LoadDataSetLayer(layer, ds)
+	})
+	return
+}
+
+// LoadAndWatchSourceLayer is equal to Conf.LoadAndWatchSourceLayer(layer, source).
+func LoadAndWatchSourceLayer(layer Layer, source Source) error {
+	return Conf.LoadAndWatchSourceLayer(layer, source)
+}
+
+// LoadAndWatchSourceLayer is the same as LoadSourceLayer, but also watches
+// the source after loading the source successfully. So the option removed
+// from the source falls back to the next layer.
+func (c *Config) LoadAndWatchSourceLayer(layer Layer, source Source) (err error) {
+	load := func(ds DataSet) error { return c.LoadDataSetLayer(layer, ds) }
+	source = c.bindSource(source)
+	ds, err := c.loadSource(source, load)
+	if err == nil {
+		go c.watchSource(source, ds, load)
+	}
+	return
+}
+
+// RemoveLayer is equal to Conf.RemoveLayer(name).
+func RemoveLayer(name string) { Conf.RemoveLayer(name) }
+
+// RemoveLayer removes all the option values in the layer named name,
+// which fall back to the next layer.
+func (c *Config) RemoveLayer(name string) {
+	c.updateLayer(Layer{Name: name}, nil, OptSource{}, true)
+}
+
+// removeLayerValue removes the value of the option from the layer named name,
+// which falls back to the next layer.
+func (c *Config) removeLayerValue(name string, o *option) {
+	c.llock.Lock()
+	var changes []optChange
+	if _, ok := o.layers[name]; ok {
+		delete(o.layers, name)
+		changes = append(changes, c.updateEffective(o))
+	}
+	notify := c.queueChanges(changes)
+	c.llock.Unlock()
+
+	if notify {
+		c.notifyQueue()
+	}
+}
+
+// updateLayer updates the values of the options in the layer, and recomputes
+// the effective values of the changed options, then notifies the observers
+// in the order the changes are applied.
+//
+// If replace is true, the options which are not in values are removed
+// from the layer.
+func (c *Config) updateLayer(layer Layer, values map[*option]interface{},
+	source OptSource, replace bool) {
+	source.Layer = layer.Name
+
+	c.llock.Lock()
+	c.lseq++
+	changed := make(map[*option]struct{}, len(values))
+	if replace {
+		for _, o := range c.registry().options {
+			if _, ok := o.layers[layer.Name]; ok {
+				if _, ok = values[o]; !ok {
+					delete(o.layers, layer.Name)
+					changed[o] = struct{}{}
+				}
+			}
+		}
+	}
+
+	for o, value := range values {
+		if o.layers == nil {
+			o.layers = make(map[string]layerValue, 2)
+		}
+
+		o.layers[layer.Name] = layerValue{
+			optValue: optValue{value: value, source: source},
+			priority: layer.Priority,
+			seq:      c.lseq,
+		}
+		changed[o] = struct{}{}
+	}
+
+	changes := make([]optChange, 0, len(changed))
+	for o := range changed {
+		changes = append(changes, c.updateEffective(o))
+	}
+	notify := c.queueChanges(changes)
+	c.llock.Unlock()
+
+	if notify {
+		c.notifyQueue()
+	}
+}
+
+// unsetOpts removes the values of the options from all the layers,
+// so they fall back to the default values.
+func (c *Config) unsetOpts(opts []*option) {
+	c.llock.Lock()
+	changes := make([]optChange, 0, len(opts))
+	for _, o := range opts {
+		if len(o.layers) > 0 {
+			o.layers = nil
+			changes = append(changes, c.updateEffective(o))
+		}
+	}
+	notify := c.queueChanges(changes)
+	c.llock.Unlock()
+
+	if notify {
+		c.notifyQueue()
+	}
+}
+
+// queueChanges appends the changes into the notification queue in the order
+// they are applied, which must be called with c.llock, and reports whether
+// the caller should notify the queued changes by notifyQueue.
+func (c *Config) queueChanges(changes []optChange) (notify bool) {
+	if len(changes) > 0 {
+		c.nqueue = append(c.nqueue, changes)
+	}
+
+	if c.nbusy || len(c.nqueue) == 0 {
+		return false
+	}
+
+	c.nbusy = true
+	return true
+}
+
+// notifyQueue notifies the queued changes one batch by one batch until
+// the queue is empty. Only one goroutine notifies them at a time, so the
+// observers receive the changes in the order they are applied, even if they
+// are applied concurrently or by the observer itself.
+func (c *Config) notifyQueue() {
+	done := false
+	defer func() {
+		if !done { // The observer panics.
+			c.llock.Lock()
+			c.nbusy = false
+			c.llock.Unlock()
+		}
+	}()
+
+	for {
+		c.llock.Lock()
+		if len(c.nqueue) == 0 {
+			c.nbusy = false
+			c.llock.Unlock()
+			done = true
+			return
+		}
+
+		changes := c.nqueue[0]
+		c.nqueue[0] = nil
+		c.nqueue = c.nqueue[1:]
+		c.llock.Unlock()
+
+		c.notifyChanges(changes)
+	}
+}
+
+func (c *Config) notifyChanges(changes []optChange) {
+	var n int
+	for _, change := range changes {
+		if reflect.DeepEqual(change.old, change.new) {
+			if change.unset {
+				// The value is not changed, but it is unset,
+				// so the snapshot is changed.
+				atomic.AddUint64(&c.gen, 1)
+			}
+			continue
+		}
+		changes[n] = change
+		n++
+	}
+
+	if n == 0 {
+		return
+	}
+
+	// Sort the changes by the option name, so that the hooks and the observers
+	// are notified in the same order as the ChangeSet.
+	changes = changes[:n]
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].option.Opt().Name < changes[j].option.Opt().Name
+	})
+
+	reg := c.registry()
+	for _, hook := range reg.hooks {
+		hook.hook(changes)
+	}
+	for _, change := range changes {
+		c.observe(change.option, change.old, change.new, change.source)
+	}
+
+	var cs ChangeSet
+	for _, observer := range reg.observers {
+		if observer.batch != nil {
+			if cs == nil {
+				cs = newChangeSet(changes)
+			}
+			observer.batch(cs)
+		}
+	}
+}
+
+// loadValues calls load with c.llock, so that the values of the options
+// read by load are not changed by any other batch.
+func (c *Config) loadValues(load func()) {
+	c.llock.Lock()
+	defer c.llock.Unlock()
+	load()
+}
+
+// updateEffective recomputes and updates the effective value of the option,
+// which must be called with c.llock.
+func (c *Config) updateEffective(o *option) optChange {
+	_default := o.Opt().Default
+	change := optChange{option: o, old: _default, new: _default,
+		source: OptSource{Source: "default", Layer: "default"}}
+
+	new := o.effective()
+	old := o.value.Swap(new)
+	if old != nil {
+		change.old = old.value
+	}
+
+	if new == nil {
+		change.unset = old != nil
+	} else {
+		change.new = new.value
+		change.source = new.source
+	}
+	return change
+}
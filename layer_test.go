@@ -0,0 +1,194 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestConfigLayers(t *testing.T) {
+	c := New()
+	c.RegisterOpts(StrOpt("addr", "").D("default"), IntOpt("port", "").D(80))
+
+	var changes []string
+	c.Observe(func(name string, old, new interface{}) {
+		changes = append(changes, fmt.Sprintf("%s:%v->%v", name, old, new))
+	})
+
+	load := func(layer Layer, data string) {
+		ds := DataSet{Data: []byte(data), Format: "json", Source: layer.Name}
+		if err := c.LoadDataSetLayer(layer, ds); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check := func(addr string, port int, layer string) {
+		t.Helper()
+		if v := c.GetString("addr"); v != addr {
+			t.Errorf("expect the addr '%s', but got '%s'", addr, v)
+		}
+		if v := c.GetInt("port"); v != port {
+			t.Errorf("expect the port '%d', but got '%d'", port, v)
+		}
+		if source, _ := c.GetOptSource("addr"); source.Layer != layer {
+			t.Errorf("expect the layer '%s', but got '%s'", layer, source.Layer)
+		}
+	}
+
+	load(LayerEnv, `{"addr": "env"}`)
+	load(LayerFile, `{"addr": "file", "port": 8080}`)
+	check("env", 8080, "env")
+
+	_ = c.Set("addr", "runtime")
+	check("runtime", 8080, "runtime")
+
+	load(LayerFlag, `{"addr": "flag"}`)
+	check("runtime", 8080, "runtime")
+
+	// Remove the key from the file layer.
+	load(LayerFile, `{"addr": "file"}`)
+	check("runtime", 80, "runtime")
+	if c.OptIsSet("port") {
+		t.Errorf("expect the option '%s' to be unset", "port")
+	}
+
+	c.RemoveLayer(LayerRuntime.Name)
+	check("flag", 80, "flag")
+
+	c.RemoveLayer(LayerFlag.Name)
+	c.RemoveLayer(LayerEnv.Name)
+	check("file", 80, "file")
+
+	// The invalid value does not change the layer.
+	if err := c.LoadDataSetLayer(LayerFile, DataSet{Data: []byte(`{"port": "abc"}`), Format: "json"}); err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+	check("file", 80, "file")
+
+	expects := []string{
+		"addr:default->env",
+		"port:80->8080",
+		"addr:env->runtime",
+		"port:8080->80",
+		"addr:runtime->flag",
+		"addr:flag->env",
+		"addr:env->file",
+	}
+	if len(changes) != len(expects) {
+		t.Fatalf("expect the changes %v, but got %v", expects, changes)
+	}
+	for i, change := range changes {
+		if change != expects[i] {
+			t.Errorf("%d: expect the change '%s', but got '%s'", i, expects[i], change)
+		}
+	}
+}
+
+func TestConfigSourceLayers(t *testing.T) {
+	c := New()
+	c.RegisterOpts(StrOpt("addr", "").D("default"), IntOpt("port", "").D(80))
+
+	load := func(source, data string, args ...string) {
+		ds := DataSet{Data: []byte(data), Format: "json", Source: source, Args: args}
+		if err := c.LoadDataSet(ds); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check := func(addr string, port int, layer string) {
+		t.Helper()
+		if v := c.GetString("addr"); v != addr {
+			t.Errorf("expect the addr '%s', but got '%s'", addr, v)
+		}
+		if v := c.GetInt("port"); v != port {
+			t.Errorf("expect the port '%d', but got '%d'", port, v)
+		}
+		if source, _ := c.GetOptSource("addr"); source.Layer != layer {
+			t.Errorf("expect the layer '%s', but got '%s'", layer, source.Layer)
+		}
+	}
+
+	// Each file source occupies its own layer.
+	load("file:a", `{"addr": "a", "port": 8080}`)
+	load("file:b", `{"addr": "b"}`)
+	check("b", 8080, "file:b")
+
+	// The env source overrides the file sources.
+	load("env", `{"addr": "env"}`, "arg1")
+	check("env", 8080, "env")
+	load("flag", `{}`, "arg2")
+	if len(c.Args) != 1 || c.Args[0] != "arg1" {
+		t.Errorf("expect the arguments %v, but got %v", []string{"arg1"}, c.Args)
+	}
+
+	// The empty data clears the layer of the source.
+	load("env", "")
+	check("b", 8080, "file:b")
+	load("file:b", "")
+	check("a", 8080, "file:a")
+
+	// The data not modified does not change the layer.
+	if err := c.LoadDataSet(DataSet{Source: "file:a", NotModified: true}); err != nil {
+		t.Fatal(err)
+	}
+	check("a", 8080, "file:a")
+
+	load("file:a", "")
+	check("default", 80, "")
+}
+
+func TestSourceLayer(t *testing.T) {
+	for _, expect := range []Layer{
+		LayerEnv,
+		LayerFlag,
+		{Name: "env:prefix", Priority: PriorityEnv},
+		{Name: "file:/path/to/file", Priority: PriorityFile},
+		{Name: "url:http://127.0.0.1/path", Priority: PriorityFile},
+	} {
+		if layer := SourceLayer(expect.Name); layer != expect {
+			t.Errorf("expect the layer %+v, but got %+v", expect, layer)
+		}
+	}
+}
+
+func TestConfigNotifyOrder(t *testing.T) {
+	c := New()
+	c.RegisterOpts(IntOpt("opt1", ""), IntOpt("opt2", ""))
+
+	// The observer changes the option, which is notified after
+	// the current change instead of in the middle of it.
+	var changes []string
+	c.Observe(func(name string, old, new interface{}) {
+		changes = append(changes, fmt.Sprintf("%s:%v->%v", name, old, new))
+		if name == "opt1" {
+			_ = c.Set("opt2", new)
+		}
+	})
+	c.Observe(func(name string, old, new interface{}) {
+		changes = append(changes, fmt.Sprintf("%s:%v->%v", name, old, new))
+	})
+
+	_ = c.Set("opt1", 1)
+	expects := []string{"opt1:0->1", "opt1:0->1", "opt2:0->1", "opt2:0->1"}
+	if len(changes) != len(expects) {
+		t.Fatalf("expect the changes %v, but got %v", expects, changes)
+	}
+	for i, change := range changes {
+		if change != expects[i] {
+			t.Errorf("%d: expect the change '%s', but got '%s'", i, expects[i], change)
+		}
+	}
+}
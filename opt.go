@@ -73,6 +73,12 @@ type Opt struct {
 	// Optional?
 	Validators []Validator
 
+	// ValidatorDescs is the descriptions of the validators, such as
+	// "an integer between 1 and 100", which is used to generate the documents.
+	//
+	// Optional?
+	ValidatorDescs []string
+
 	// OnUpdate is called when the option value is updated.
 	OnUpdate func(oldValue, newValue interface{})
 }
@@ -142,6 +148,18 @@ func (o Opt) V(validators ...Validator) Opt {
 	return o
 }
 
+// DV returns a new Opt with the given described validators based on
+// the current option, which will append the validators and their descriptions.
+func (o Opt) DV(validators ...DescribedValidator) Opt {
+	for _, v := range validators {
+		o.Validators = append(o.Validators, v.Validator)
+		if v.Description != "" {
+			o.ValidatorDescs = append(o.ValidatorDescs, v.Description)
+		}
+	}
+	return o
+}
+
 // D returns a new Opt with the given default value based on the current option.
 func (o Opt) D(_default interface{}) Opt {
 	if _default == nil {
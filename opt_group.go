@@ -123,6 +123,63 @@ func (g *OptGroup) Set(name string, value interface{}) error {
 	return g.config.Set(g.prefix+name, value)
 }
 
+// Unset removes the value of the option named name, like Config.Unset.
+func (g *OptGroup) Unset(name string) error {
+	return g.config.Unset(g.prefix + name)
+}
+
+// Reset resets all the options in the group and its sub-groups
+// to the default values, like Config.Unset.
+func (g *OptGroup) Reset() {
+	// The names in the registry have been fixed by fixOptionName.
+	prefix := g.config.fixOptionName(g.prefix)
+	opts := make([]*option, 0, 8)
+	for name, opt := range g.config.registry().options {
+		if strings.HasPrefix(name, prefix) {
+			opts = append(opts, opt)
+		}
+	}
+	g.config.unsetOpts(opts)
+}
+
+// Observe appends the observer to watch the change of the option values
+// in the group, which receives the option name relative to the group,
+// and returns a function to remove the observer.
+func (g *OptGroup) Observe(observe Observer) (cancel func()) {
+	prefix := g.prefix
+	return g.config.addObservers(observer{
+		observe: func(name string, old, new interface{}, _ OptSource) {
+			if strings.HasPrefix(name, prefix) {
+				observe(name[len(prefix):], old, new)
+			}
+		},
+	})
+}
+
+// ObserveBatch appends the observer to watch the changes of the option values
+// in the group, which receives the option names relative to the group,
+// and returns a function to remove the observer.
+//
+// The observer is not called if no option in the group is changed.
+func (g *OptGroup) ObserveBatch(observe BatchObserver) (cancel func()) {
+	prefix := g.prefix
+	return g.config.addObservers(observer{
+		batch: func(changes ChangeSet) {
+			var cs ChangeSet
+			for _, change := range changes {
+				if strings.HasPrefix(change.Name, prefix) {
+					change.Name = change.Name[len(prefix):]
+					cs = append(cs, change)
+				}
+			}
+
+			if len(cs) > 0 {
+				observe(cs)
+			}
+		},
+	})
+}
+
 // Get returns the value of the option named name.
 //
 // Return nil if this option does not exist.
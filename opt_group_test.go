@@ -203,3 +203,39 @@ func TestOptGroupEmptyName(t *testing.T) {
 		}
 	}
 }
+
+func TestOptGroupObserve(t *testing.T) {
+	c := New()
+	c.RegisterOpts(StrOpt("name", ""))
+	db := c.Group("db")
+	db.RegisterOpts(StrOpt("host", ""), IntOpt("port", ""))
+
+	var changes []interface{}
+	cancel := db.Observe(func(name string, old, new interface{}) {
+		changes = append(changes, name, old, new)
+	})
+
+	var changesets []ChangeSet
+	bcancel := db.ObserveBatch(func(changes ChangeSet) {
+		changesets = append(changesets, changes)
+	})
+
+	_ = c.Set("name", "abc")
+	_ = c.LoadMap(map[string]interface{}{"name": "xyz", "db.port": 3306}, true)
+	if !reflect.DeepEqual(changes, []interface{}{"port", 0, 3306}) {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+
+	if len(changesets) != 1 {
+		t.Errorf("expect %d change sets, but got %d", 1, len(changesets))
+	} else if names := changesets[0].Names(); !reflect.DeepEqual(names, []string{"port"}) {
+		t.Errorf("unexpected changed options: %v", names)
+	}
+
+	cancel()
+	bcancel()
+	_ = db.Set("host", "127.0.0.1")
+	if len(changes) != 3 || len(changesets) != 1 {
+		t.Errorf("the observers are not removed")
+	}
+}
@@ -35,62 +35,71 @@ func (c *Config) NewOptProxy(opt Opt) OptProxy {
 }
 
 // Name returns the name of the option.
-func (o *OptProxy) Name() string { return o.option.opt.Name }
+func (o *OptProxy) Name() string { return o.option.Opt().Name }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxy) Opt() Opt { return o.option.opt }
+func (o *OptProxy) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxy) Get() interface{} {
-	return o.config.Get(o.option.opt.Name)
+	return o.config.Get(o.option.Opt().Name)
 }
 
 // Set sets the value of the option to value.
 func (o *OptProxy) Set(value interface{}) (err error) {
-	return o.config.Set(o.option.opt.Name, value)
+	return o.config.Set(o.option.Opt().Name, value)
 }
 
 // OnUpdate resets the update callback function of the option and returns itself.
 func (o *OptProxy) OnUpdate(callback func(old, new interface{})) *OptProxy {
-	o.option.opt.OnUpdate = callback
+	o.option.updateOpt(func(opt Opt) Opt {
+		opt.OnUpdate = callback
+		return opt
+	})
 	return o
 }
 
 // IsCli resets the cli flag of the option and returns itself.
 func (o *OptProxy) IsCli(cli bool) *OptProxy {
-	o.option.opt.IsCli = cli
+	o.option.updateOpt(func(opt Opt) Opt {
+		opt.IsCli = cli
+		return opt
+	})
 	return o
 }
 
 // Aliases appends the aliases of the option and returns itself.
 func (o *OptProxy) Aliases(aliases ...string) *OptProxy {
 	for _, alias := range aliases {
-		o.config.setOptAlias(alias, o.option.opt.Name)
+		o.config.setOptAlias(alias, o.option.Opt().Name)
 	}
 	return o
 }
 
 // Short resets the short name of the option and returns itself.
 func (o *OptProxy) Short(short string) *OptProxy {
-	o.option.opt = o.option.opt.S(short)
+	o.option.updateOpt(func(opt Opt) Opt { return opt.S(short) })
 	return o
 }
 
 // Validators appends the validators of the option and returns itself.
 func (o *OptProxy) Validators(validators ...Validator) *OptProxy {
-	o.option.opt = o.option.opt.V(validators...)
+	o.option.updateOpt(func(opt Opt) Opt {
+		opt.Validators = opt.Validators[:len(opt.Validators):len(opt.Validators)]
+		return opt.V(validators...)
+	})
 	return o
 }
 
 // Default resets the default value of the option and returns itself.
 func (o *OptProxy) Default(_default interface{}) *OptProxy {
-	o.option.opt = o.option.opt.D(_default)
+	o.option.updateOpt(func(opt Opt) Opt { return opt.D(_default) })
 	return o
 }
 
 // Parser resets the parser of the option and returns itself.
 func (o *OptProxy) Parser(parser Parser) *OptProxy {
-	o.option.opt = o.option.opt.P(parser)
+	o.option.updateOpt(func(opt Opt) Opt { return opt.P(parser) })
 	return o
 }
 
@@ -267,7 +276,7 @@ func (c *Config) NewBool(name string, _default bool, help string) *OptProxyBool
 func (o *OptProxyBool) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyBool) Opt() Opt { return o.option.opt }
+func (o *OptProxyBool) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyBool) Get() bool { return o.OptProxy.Get().(bool) }
@@ -340,7 +349,7 @@ func (c *Config) NewInt(name string, _default int, help string) *OptProxyInt {
 func (o *OptProxyInt) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyInt) Opt() Opt { return o.option.opt }
+func (o *OptProxyInt) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyInt) Get() int { return o.OptProxy.Get().(int) }
@@ -413,7 +422,7 @@ func (c *Config) NewInt16(name string, _default int16, help string) *OptProxyInt
 func (o *OptProxyInt16) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyInt16) Opt() Opt { return o.option.opt }
+func (o *OptProxyInt16) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyInt16) Get() int16 { return o.OptProxy.Get().(int16) }
@@ -486,7 +495,7 @@ func (c *Config) NewInt32(name string, _default int32, help string) *OptProxyInt
 func (o *OptProxyInt32) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyInt32) Opt() Opt { return o.option.opt }
+func (o *OptProxyInt32) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyInt32) Get() int32 { return o.OptProxy.Get().(int32) }
@@ -559,7 +568,7 @@ func (c *Config) NewInt64(name string, _default int64, help string) *OptProxyInt
 func (o *OptProxyInt64) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyInt64) Opt() Opt { return o.option.opt }
+func (o *OptProxyInt64) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyInt64) Get() int64 { return o.OptProxy.Get().(int64) }
@@ -632,7 +641,7 @@ func (c *Config) NewUint(name string, _default uint, help string) *OptProxyUint
 func (o *OptProxyUint) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyUint) Opt() Opt { return o.option.opt }
+func (o *OptProxyUint) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyUint) Get() uint { return o.OptProxy.Get().(uint) }
@@ -705,7 +714,7 @@ func (c *Config) NewUint16(name string, _default uint16, help string) *OptProxyU
 func (o *OptProxyUint16) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyUint16) Opt() Opt { return o.option.opt }
+func (o *OptProxyUint16) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyUint16) Get() uint16 { return o.OptProxy.Get().(uint16) }
@@ -778,7 +787,7 @@ func (c *Config) NewUint32(name string, _default uint32, help string) *OptProxyU
 func (o *OptProxyUint32) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyUint32) Opt() Opt { return o.option.opt }
+func (o *OptProxyUint32) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyUint32) Get() uint32 { return o.OptProxy.Get().(uint32) }
@@ -851,7 +860,7 @@ func (c *Config) NewUint64(name string, _default uint64, help string) *OptProxyU
 func (o *OptProxyUint64) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyUint64) Opt() Opt { return o.option.opt }
+func (o *OptProxyUint64) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyUint64) Get() uint64 { return o.OptProxy.Get().(uint64) }
@@ -924,7 +933,7 @@ func (c *Config) NewFloat64(name string, _default float64, help string) *OptProx
 func (o *OptProxyFloat64) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyFloat64) Opt() Opt { return o.option.opt }
+func (o *OptProxyFloat64) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyFloat64) Get() float64 { return o.OptProxy.Get().(float64) }
@@ -997,7 +1006,7 @@ func (c *Config) NewString(name, _default, help string) *OptProxyString {
 func (o *OptProxyString) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyString) Opt() Opt { return o.option.opt }
+func (o *OptProxyString) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyString) Get() string { return o.OptProxy.Get().(string) }
@@ -1070,7 +1079,7 @@ func (c *Config) NewDuration(name string, _default time.Duration, help string) *
 func (o *OptProxyDuration) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyDuration) Opt() Opt { return o.option.opt }
+func (o *OptProxyDuration) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyDuration) Get() time.Duration { return o.OptProxy.Get().(time.Duration) }
@@ -1143,7 +1152,7 @@ func (c *Config) NewTime(name string, _default time.Time, help string) *OptProxy
 func (o *OptProxyTime) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyTime) Opt() Opt { return o.option.opt }
+func (o *OptProxyTime) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyTime) Get() time.Time { return o.OptProxy.Get().(time.Time) }
@@ -1216,7 +1225,7 @@ func (c *Config) NewStringSlice(name string, _default []string, help string) *Op
 func (o *OptProxyStringSlice) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyStringSlice) Opt() Opt { return o.option.opt }
+func (o *OptProxyStringSlice) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyStringSlice) Get() []string { return o.OptProxy.Get().([]string) }
@@ -1289,7 +1298,7 @@ func (c *Config) NewIntSlice(name string, _default []int, help string) *OptProxy
 func (o *OptProxyIntSlice) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyIntSlice) Opt() Opt { return o.option.opt }
+func (o *OptProxyIntSlice) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyIntSlice) Get() []int { return o.OptProxy.Get().([]int) }
@@ -1362,7 +1371,7 @@ func (c *Config) NewUintSlice(name string, _default []uint, help string) *OptPro
 func (o *OptProxyUintSlice) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyUintSlice) Opt() Opt { return o.option.opt }
+func (o *OptProxyUintSlice) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyUintSlice) Get() []uint { return o.OptProxy.Get().([]uint) }
@@ -1435,7 +1444,7 @@ func (c *Config) NewFloat64Slice(name string, _default []float64, help string) *
 func (o *OptProxyFloat64Slice) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyFloat64Slice) Opt() Opt { return o.option.opt }
+func (o *OptProxyFloat64Slice) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyFloat64Slice) Get() []float64 { return o.OptProxy.Get().([]float64) }
@@ -1508,7 +1517,7 @@ func (c *Config) NewDurationSlice(name string, _default []time.Duration, help st
 func (o *OptProxyDurationSlice) Name() string { return o.OptProxy.Name() }
 
 // Opt returns the registered and proxied option.
-func (o *OptProxyDurationSlice) Opt() Opt { return o.option.opt }
+func (o *OptProxyDurationSlice) Opt() Opt { return o.option.Opt() }
 
 // Get returns the value of the option.
 func (o *OptProxyDurationSlice) Get() []time.Duration { return o.OptProxy.Get().([]time.Duration) }
@@ -0,0 +1,138 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Value returns the value of the option named name as the type T,
+// which works for any option type, including the custom type.
+//
+// If c is nil, use Conf instead.
+//
+// Return an error wrapping ErrNoOpt if the option does not exist,
+// or an error if the value of the option is not typed T.
+func Value[T any](c *Config, name string) (value T, err error) {
+	if c == nil {
+		c = Conf
+	}
+
+	opt, ok := c.getOption(name)
+	if !ok {
+		return value, fmt.Errorf("%w named '%s'", ErrNoOpt, name)
+	}
+
+	v := opt.Get()
+	if value, ok = v.(T); !ok {
+		err = fmt.Errorf("the value of the option named '%s' is typed %T, not %s",
+			name, v, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return
+}
+
+// TypedOpt is a generic proxy for the option whose value is typed T,
+// which can be used to modify the attributions of the option and
+// update its value directly, like OptProxyInt, etc.
+type TypedOpt[T any] struct{ OptProxy }
+
+// NewTypedOpt registers the option into c and returns a typed proxy of it.
+// If c is nil, use Conf instead.
+//
+// Notice: it will panic if the default value of the option is not typed T.
+func NewTypedOpt[T any](c *Config, opt Opt) *TypedOpt[T] {
+	if c == nil {
+		c = Conf
+	}
+	checkTypedDefault[T](opt)
+	return &TypedOpt[T]{c.NewOptProxy(opt)}
+}
+
+// ToTypedOpt converts the option proxy to the typed proxy, such as the proxy
+// returned by OptGroup.NewOptProxy.
+//
+// Notice: it will panic if the default value of the option is not typed T.
+func ToTypedOpt[T any](proxy OptProxy) *TypedOpt[T] {
+	checkTypedDefault[T](proxy.Opt())
+	return &TypedOpt[T]{proxy}
+}
+
+func checkTypedDefault[T any](opt Opt) {
+	if _, ok := opt.Default.(T); !ok {
+		panic(fmt.Errorf("the default value of the option named '%s' is typed %T, not %s",
+			opt.Name, opt.Default, reflect.TypeOf((*T)(nil)).Elem()))
+	}
+}
+
+// Get returns the value of the option.
+func (o *TypedOpt[T]) Get() T { return o.OptProxy.Get().(T) }
+
+// Set sets the value of the option to value, which is validated by the option.
+func (o *TypedOpt[T]) Set(value T) (err error) {
+	return o.OptProxy.Set(value)
+}
+
+// SetString parses the string value by the parser of the option,
+// such as "error" for the log level, and sets the value of the option to it.
+func (o *TypedOpt[T]) SetString(value string) (err error) {
+	return o.OptProxy.Set(value)
+}
+
+// OnUpdate resets the update callback of the option and returns itself.
+func (o *TypedOpt[T]) OnUpdate(f func(old, new T)) *TypedOpt[T] {
+	o.OptProxy.OnUpdate(func(old, new interface{}) {
+		oldvalue, _ := old.(T)
+		newvalue, _ := new.(T)
+		f(oldvalue, newvalue)
+	})
+	return o
+}
+
+// IsCli resets the cli flag of the option and returns itself.
+func (o *TypedOpt[T]) IsCli(cli bool) *TypedOpt[T] {
+	o.OptProxy.IsCli(cli)
+	return o
+}
+
+// Aliases appends the aliases of the option and returns itself.
+func (o *TypedOpt[T]) Aliases(aliases ...string) *TypedOpt[T] {
+	o.OptProxy.Aliases(aliases...)
+	return o
+}
+
+// Short resets the short name of the option and returns itself.
+func (o *TypedOpt[T]) Short(short string) *TypedOpt[T] {
+	o.OptProxy.Short(short)
+	return o
+}
+
+// Validators appends the validators of the option and returns itself.
+func (o *TypedOpt[T]) Validators(validators ...Validator) *TypedOpt[T] {
+	o.OptProxy.Validators(validators...)
+	return o
+}
+
+// Default resets the default value of the option and returns itself.
+func (o *TypedOpt[T]) Default(_default T) *TypedOpt[T] {
+	o.OptProxy.Default(_default)
+	return o
+}
+
+// Parser resets the parser of the option and returns itself.
+func (o *TypedOpt[T]) Parser(parser Parser) *TypedOpt[T] {
+	o.OptProxy.Parser(parser)
+	return o
+}
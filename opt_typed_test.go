@@ -0,0 +1,121 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testLevel int
+
+func parseTestLevel(v interface{}) (interface{}, error) {
+	switch s := v.(type) {
+	case testLevel:
+		return s, nil
+	case string:
+		switch strings.ToLower(s) {
+		case "debug":
+			return testLevel(0), nil
+		case "info":
+			return testLevel(1), nil
+		case "error":
+			return testLevel(2), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid level '%v'", v)
+}
+
+func TestTypedOpt(t *testing.T) {
+	c := New()
+	level := NewTypedOpt[testLevel](c, NewOpt("level", "The log level.", testLevel(1), parseTestLevel))
+	port := ToTypedOpt[int](c.Group("server").NewOptProxy(IntOpt("port", "")))
+	port.Default(80).Validators(NewPortValidator())
+
+	var changes []testLevel
+	level.OnUpdate(func(old, new testLevel) { changes = append(changes, old, new) })
+
+	if v := level.Get(); v != 1 {
+		t.Errorf("expect the level %d, but got %d", 1, v)
+	}
+
+	if err := level.SetString("error"); err != nil {
+		t.Error(err)
+	} else if v := level.Get(); v != 2 {
+		t.Errorf("expect the level %d, but got %d", 2, v)
+	} else if len(changes) != 2 || changes[0] != 1 || changes[1] != 2 {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+
+	if err := port.Set(70000); err == nil {
+		t.Errorf("expect an error, but got nil")
+	} else if v := port.Get(); v != 80 {
+		t.Errorf("expect the port %d, but got %d", 80, v)
+	}
+
+	if err := level.Set(testLevel(0)); err != nil {
+		t.Error(err)
+	} else if err = level.SetString("abc"); err == nil {
+		t.Errorf("expect an error, but got nil")
+	} else if v := level.Get(); v != 0 {
+		t.Errorf("expect the level %d, but got %d", 0, v)
+	}
+	_ = level.Set(testLevel(2))
+
+	if v, err := Value[testLevel](c, "level"); err != nil {
+		t.Error(err)
+	} else if v != 2 {
+		t.Errorf("expect the level %d, but got %d", 2, v)
+	}
+
+	if v, err := Value[int](c, "server.port"); err != nil {
+		t.Error(err)
+	} else if v != 80 {
+		t.Errorf("expect the port %d, but got %d", 80, v)
+	}
+
+	if _, err := Value[string](c, "level"); err == nil {
+		t.Errorf("expect an error, but got nil")
+	} else if expect := "the value of the option named 'level' is typed gconf.testLevel, not string"; err.Error() != expect {
+		t.Errorf("expect the error '%s', but got '%s'", expect, err)
+	}
+
+	if _, err := Value[int](c, "noopt"); !errors.Is(err, ErrNoOpt) {
+		t.Errorf("expect the error ErrNoOpt, but got '%v'", err)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expect a panic, but got nil")
+			}
+		}()
+		NewTypedOpt[string](c, IntOpt("int", ""))
+	}()
+}
+
+func ExampleValue() {
+	c := New()
+	c.RegisterOpts(NewOpt("level", "", testLevel(1), parseTestLevel))
+	_ = c.Set("level", "error")
+
+	level, err := Value[testLevel](c, "level")
+	fmt.Println(level, err)
+
+	// Output:
+	// 2 <nil>
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import "slices"
+
+// registry is the copy-on-write registry of the options, the decoders,
+// the encoders, the observers and the hooks, which must not be modified
+// after it is stored into Config. So it can be read without the lock by any goroutine.
+type registry struct {
+	options   map[string]*option
+	aliases   map[string]string
+	daliases  map[string]string
+	decoders  map[string]Decoder
+	encoders  map[string]Encoder
+	observers []observer
+	hooks     []observer
+	oid       uint64 // The id of the last added observer or hook.
+}
+
+// observer is an added observer or hook, which is identified by id,
+// and one of observe, batch and hook is set.
+type observer struct {
+	id      uint64
+	observe SourceObserver
+	batch   BatchObserver
+	hook    batchHook
+}
+
+// batchHook is called with the changed options of each batch
+// after all of them are applied and before notifying the observers.
+type batchHook func(changes []optChange)
+
+func newRegistry() *registry {
+	return &registry{
+		options:  make(map[string]*option, 32),
+		aliases:  make(map[string]string, 8),
+		daliases: make(map[string]string, 4),
+		decoders: make(map[string]Decoder, 8),
+		encoders: make(map[string]Encoder, 4),
+	}
+}
+
+func (r *registry) clone() *registry {
+	return &registry{
+		options:   cloneMap(r.options),
+		aliases:   cloneMap(r.aliases),
+		daliases:  cloneMap(r.daliases),
+		decoders:  cloneMap(r.decoders),
+		encoders:  cloneMap(r.encoders),
+		observers: append([]observer(nil), r.observers...),
+		hooks:     append([]observer(nil), r.hooks...),
+		oid:       r.oid,
+	}
+}
+
+func cloneMap[V any](m map[string]V) map[string]V {
+	n := make(map[string]V, len(m)+1)
+	for k, v := range m {
+		n[k] = v
+	}
+	return n
+}
+
+// getOption returns the option by the name or the alias.
+func (r *registry) getOption(name string) (opt *option, ok bool) {
+	if opt, ok = r.options[name]; !ok {
+		if alias, exist := r.aliases[name]; exist {
+			opt, ok = r.options[alias]
+		}
+	}
+	return
+}
+
+// registry returns the current registry, which must not be modified.
+func (c *Config) registry() *registry { return c.reg.Load() }
+
+// updateRegistry updates a copy of the current registry by update,
+// then replaces the current registry with it.
+func (c *Config) updateRegistry(update func(r *registry)) {
+	c.rlock.Lock()
+	defer c.rlock.Unlock()
+
+	r := c.reg.Load().clone()
+	update(r)
+	c.reg.Store(r)
+}
+
+// addObservers adds the observers and the hooks,
+// and returns a function to remove them.
+func (c *Config) addObservers(observers ...observer) (cancel func()) {
+	if len(observers) == 0 {
+		return func() {}
+	}
+
+	c.updateRegistry(func(r *registry) {
+		for i := range observers {
+			r.oid++
+			observers[i].id = r.oid
+			if observers[i].hook != nil {
+				r.hooks = append(r.hooks, observers[i])
+			} else {
+				r.observers = append(r.observers, observers[i])
+			}
+		}
+	})
+
+	return func() {
+		remove := func(o observer) bool {
+			return slices.ContainsFunc(observers, func(obs observer) bool {
+				return obs.id == o.id
+			})
+		}
+
+		c.updateRegistry(func(r *registry) {
+			r.observers = slices.DeleteFunc(r.observers, remove)
+			r.hooks = slices.DeleteFunc(r.hooks, remove)
+		})
+	}
+}
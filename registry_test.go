@@ -0,0 +1,92 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Run it with "go test -race".
+func TestConfigConcurrentRegistry(t *testing.T) {
+	c := New()
+	c.RegisterOpts(IntOpt("opt", ""))
+
+	const count = 100
+	var wg sync.WaitGroup
+	wg.Add(5)
+
+	go func() { // Register the options lazily, like the plugins.
+		defer wg.Done()
+		for i := 0; i < count; i++ {
+			name := fmt.Sprintf("plugin%d", i)
+			c.Group(name).RegisterOpts(IntOpt("opt", "").As("alias"))
+			proxy := c.NewOptProxy(IntOpt(name+"_proxy", ""))
+			proxy.Default(i).Validators(NewIntegerRangeValidator(0, count)).Aliases(name + "_alias")
+			if i%2 == 0 {
+				c.UnregisterOpts(name + ".opt")
+			}
+		}
+	}()
+
+	go func() { // Load the data like the watchers.
+		defer wg.Done()
+		for i := 0; i < count; i++ {
+			data := fmt.Sprintf(`{"opt": %d, "plugin%d": {"opt": %d}}`, i, i, i)
+			if err := c.LoadDataSet(DataSet{Data: []byte(data), Format: "json"}, true); err != nil {
+				t.Error(err)
+			}
+		}
+	}()
+
+	go func() { // Read the options.
+		defer wg.Done()
+		for i := 0; i < count; i++ {
+			_ = c.GetInt("opt")
+			_ = c.Get(fmt.Sprintf("plugin%d.opt", i))
+			_ = c.HasOpt(fmt.Sprintf("plugin%d.alias", i))
+			_, _ = c.GetOpt(fmt.Sprintf("plugin%d_proxy", i))
+			_, _ = c.Snapshot()
+			_ = c.GetAllOpts()
+		}
+	}()
+
+	go func() { // Observe the options.
+		defer wg.Done()
+		for i := 0; i < count; i++ {
+			c.Observe(func(string, interface{}, interface{}) {})
+		}
+	}()
+
+	go func() { // Add the decoders.
+		defer wg.Done()
+		for i := 0; i < count; i++ {
+			c.AddDecoder(fmt.Sprintf("json%d", i), NewJSONDecoder())
+			c.AddDecoderTypeAliases("json", fmt.Sprintf("json_%d", i))
+			_ = c.GetDecoder("json")
+		}
+	}()
+
+	wg.Wait()
+
+	if opts := c.GetAllOpts(); len(opts) != 1+count+count/2 {
+		t.Errorf("expect %d options, but got %d", 1+count+count/2, len(opts))
+	} else if v := c.GetInt("opt"); v != count-1 {
+		t.Errorf("expect the value %d, but got %d", count-1, v)
+	} else if c.GetDecoder("json_1") == nil {
+		t.Errorf("expect the decoder alias '%s'", "json_1")
+	}
+}
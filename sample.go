@@ -0,0 +1,193 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// optGroupTree is the tree of the options organized by the group.
+type optGroupTree struct {
+	key    string // The name of the group relative to the parent group.
+	name   string // The full name of the group, which is joined by ".".
+	opts   []Opt  // The options directly in the group, the name of which is relative.
+	groups map[string]*optGroupTree
+}
+
+func (t *optGroupTree) sortedGroups() []*optGroupTree {
+	groups := make([]*optGroupTree, 0, len(t.groups))
+	for _, group := range t.groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].name < groups[j].name })
+	return groups
+}
+
+// getOptGroupTree returns the tree of the given options by the group separator.
+func (c *Config) getOptGroupTree(opts []Opt) *optGroupTree {
+	root := &optGroupTree{groups: make(map[string]*optGroupTree)}
+	for _, opt := range opts {
+		node := root
+		parts := strings.Split(opt.Name, c.gsep)
+		for _, part := range parts[:len(parts)-1] {
+			sub, ok := node.groups[part]
+			if !ok {
+				name := part
+				if node.name != "" {
+					name = node.name + "." + part
+				}
+				sub = &optGroupTree{key: part, name: name, groups: make(map[string]*optGroupTree)}
+				node.groups[part] = sub
+			}
+			node = sub
+		}
+
+		opt.Name = parts[len(parts)-1]
+		node.opts = append(node.opts, opt)
+	}
+	return root
+}
+
+// GenerateSample is equal to Conf.GenerateSample(format, w).
+func GenerateSample(format string, w io.Writer) error {
+	return Conf.GenerateSample(format, w)
+}
+
+// GenerateSample generates a sample configuration file of all the registered
+// options, in which the options are grouped by the group, the help of each
+// option is written as the comment, and the default value is commented out.
+//
+// format supports "ini", "yaml" or "yml", "toml" and "json" (which is JSONC
+// and can be decoded by NewJSONDecoder).
+func (c *Config) GenerateSample(format string, w io.Writer) (err error) {
+	tree := c.getOptGroupTree(c.GetAllOpts())
+	buf := bufio.NewWriter(w)
+	switch strings.ToLower(format) {
+	case "ini":
+		writeIniSample(buf, tree)
+	case "toml":
+		writeTOMLSample(buf, tree)
+	case "yaml", "yml":
+		writeYAMLSample(buf, tree, "")
+	case "json":
+		buf.WriteString("{\n")
+		writeJSONSample(buf, tree, "  ")
+		buf.WriteString("}\n")
+	default:
+		return fmt.Errorf("unsupported sample format '%s'", format)
+	}
+	return buf.Flush()
+}
+
+func writeSampleComment(w *bufio.Writer, indent, comment string, opt Opt) {
+	if opt.Help != "" {
+		for _, line := range strings.Split(strings.TrimSpace(opt.Help), "\n") {
+			fmt.Fprintf(w, "%s%s %s\n", indent, comment, strings.TrimSpace(line))
+		}
+	}
+	if len(opt.Aliases) > 0 {
+		fmt.Fprintf(w, "%s%s Aliases: %s\n", indent, comment, strings.Join(opt.Aliases, ", "))
+	}
+}
+
+// formatSampleValue formats the default value as the json value,
+// which is also valid in yaml and toml.
+func formatSampleValue(value interface{}) string {
+	buf := bytes.NewBuffer(nil)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(toEncodedValue(value)); err != nil {
+		return fmt.Sprintf("%q", fmt.Sprint(value))
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+func writeIniSample(w *bufio.Writer, tree *optGroupTree) {
+	if len(tree.opts) > 0 {
+		if tree.name != "" {
+			fmt.Fprintf(w, "[%s]\n", tree.name)
+		}
+
+		for _, opt := range tree.opts {
+			writeSampleComment(w, "", "#", opt)
+			value := toEncodedValue(opt.Default)
+			switch vf := reflect.ValueOf(value); vf.Kind() {
+			case reflect.Slice, reflect.Array:
+				if vf.Len() == 0 {
+					fmt.Fprintf(w, "; %s[] =\n", opt.Name)
+				}
+				for i, _len := 0, vf.Len(); i < _len; i++ {
+					fmt.Fprintf(w, "; %s[] = %s\n", opt.Name, quoteIniValue(fmt.Sprint(vf.Index(i).Interface())))
+				}
+			default:
+				fmt.Fprintf(w, "; %s = %s\n", opt.Name, quoteIniValue(fmt.Sprint(value)))
+			}
+			w.WriteByte('\n')
+		}
+	}
+
+	for _, group := range tree.sortedGroups() {
+		writeIniSample(w, group)
+	}
+}
+
+func writeTOMLSample(w *bufio.Writer, tree *optGroupTree) {
+	if len(tree.opts) > 0 {
+		if tree.name != "" {
+			fmt.Fprintf(w, "[%s]\n", tree.name)
+		}
+
+		for _, opt := range tree.opts {
+			writeSampleComment(w, "", "#", opt)
+			fmt.Fprintf(w, "# %s = %s\n\n", opt.Name, formatSampleValue(opt.Default))
+		}
+	}
+
+	for _, group := range tree.sortedGroups() {
+		writeTOMLSample(w, group)
+	}
+}
+
+func writeYAMLSample(w *bufio.Writer, tree *optGroupTree, indent string) {
+	for _, opt := range tree.opts {
+		writeSampleComment(w, indent, "#", opt)
+		fmt.Fprintf(w, "%s# %s: %s\n\n", indent, opt.Name, formatSampleValue(opt.Default))
+	}
+
+	for _, group := range tree.sortedGroups() {
+		fmt.Fprintf(w, "%s%s:\n", indent, group.key)
+		writeYAMLSample(w, group, indent+"  ")
+	}
+}
+
+func writeJSONSample(w *bufio.Writer, tree *optGroupTree, indent string) {
+	for _, opt := range tree.opts {
+		writeSampleComment(w, indent, "//", opt)
+		fmt.Fprintf(w, "%s// %q: %s,\n\n", indent, opt.Name, formatSampleValue(opt.Default))
+	}
+
+	for _, group := range tree.sortedGroups() {
+		fmt.Fprintf(w, "%s%q: {\n", indent, group.key)
+		writeJSONSample(w, group, indent+"  ")
+		fmt.Fprintf(w, "%s},\n", indent)
+	}
+}
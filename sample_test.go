@@ -0,0 +1,71 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func newSampleTestConfig() *Config {
+	c := New()
+	c.RegisterOpts(StrOpt("name", "The name of the app.").D("demo").As("appname"))
+	c.Group("db").RegisterOpts(IntOpt("port", "The port.\nThe second line.").D(3306))
+	c.Group("db.primary").RegisterOpts(StrSliceOpt("addrs", "The addresses.").D("a,b"))
+	return c
+}
+
+func TestConfigGenerateSample(t *testing.T) {
+	c := newSampleTestConfig()
+	for _, format := range []string{"ini", "yaml", "toml", "json"} {
+		buf := bytes.NewBuffer(nil)
+		if err := c.GenerateSample(format, buf); err != nil {
+			t.Errorf("%s: %s", format, err)
+			continue
+		}
+
+		// The generated sample can be decoded, and all the options are commented out.
+		if err := c.LoadDataSet(DataSet{Format: format, Data: buf.Bytes()}); err != nil {
+			t.Errorf("%s: %s\n%s", format, err, buf.String())
+		} else if _, snap := c.Snapshot(); len(snap) > 0 {
+			t.Errorf("%s: unexpected the options: %v", format, snap)
+		}
+	}
+
+	if err := c.GenerateSample("xml", bytes.NewBuffer(nil)); err == nil {
+		t.Errorf("expect an error for the unsupported format")
+	}
+}
+
+func ExampleConfig_GenerateSample() {
+	c := newSampleTestConfig()
+	_ = c.GenerateSample("yaml", os.Stdout)
+
+	// Output:
+	// # The name of the app.
+	// # Aliases: appname
+	// # name: "demo"
+	//
+	// db:
+	//   # The port.
+	//   # The second line.
+	//   # port: 3306
+	//
+	//   primary:
+	//     # The addresses.
+	//     # addrs: ["a","b"]
+	//
+}
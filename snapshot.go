@@ -42,7 +42,7 @@ func (c *Config) LoadBackupFile(filename string) (err error) {
 		if err = json.Unmarshal(data, &ms); err != nil {
 			c.errorf("the backup file '%s' format is error: %s", filename, err)
 			return
-		} else if err = c.LoadMap(ms); err != nil {
+		} else if err = c.loadMap(ms, false, newOptSource("backup:"+filename, "", time.Time{})); err != nil {
 			return
 		}
 	}
@@ -100,11 +100,54 @@ func (c *Config) writeSnapshotIntoFile(filename string) {
 //	}
 func (c *Config) Snapshot() (generation uint64, snap map[string]interface{}) {
 	generation = atomic.LoadUint64(&c.gen)
-	snap = make(map[string]interface{}, len(c.options))
-	for name, opt := range c.options {
+	options := c.registry().options
+	snap = make(map[string]interface{}, len(options))
+	for name, opt := range options {
 		if v := opt.GetValue(); v != nil {
 			snap[name] = v
 		}
 	}
 	return
 }
+
+// OptValue represents the value of the option with its source.
+type OptValue struct {
+	Value interface{} `json:"value"`
+	OptSource
+}
+
+// SnapshotWithSources is the same as Snapshot, but also returns the source
+// which sets the value of each option.
+//
+// For example,
+//
+//	map[string]OptValue {
+//	    "opt1": {Value: "value1", OptSource: OptSource{Source: "flag", ...}},
+//	    "group1.opt2": {Value: "value2", OptSource: OptSource{Source: "file:/path/to/file", ...}},
+//	    // ...
+//	}
+func (c *Config) SnapshotWithSources() (generation uint64, snap map[string]OptValue) {
+	generation = atomic.LoadUint64(&c.gen)
+	options := c.registry().options
+	snap = make(map[string]OptValue, len(options))
+	for name, opt := range options {
+		if v, ok := opt.load(); ok {
+			snap[name] = OptValue{Value: v.value, OptSource: v.source}
+		}
+	}
+	return
+}
+
+// GetOptSource returns the source which sets the current value
+// of the option named name.
+//
+// If the option does not exist or has not been set, return false.
+func (c *Config) GetOptSource(name string) (source OptSource, ok bool) {
+	if opt, exist := c.getOption(name); exist {
+		var v optValue
+		if v, ok = opt.load(); ok {
+			source = v.source
+		}
+	}
+	return
+}
@@ -14,7 +14,10 @@
 
 package gconf
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestConfig_Snapshot(t *testing.T) {
 	config := New()
@@ -72,3 +75,54 @@ func TestConfig_Snapshot(t *testing.T) {
 	}
 
 }
+
+func TestConfig_GetOptSource(t *testing.T) {
+	config := New()
+	config.RegisterOpts(StrOpt("opt1", ""), IntOpt("opt2", ""), IntOpt("opt3", ""))
+
+	var observed []OptSource
+	config.ObserveWithSource(func(name string, old, new interface{}, source OptSource) {
+		observed = append(observed, source)
+	})
+
+	if _, ok := config.GetOptSource("opt1"); ok {
+		t.Errorf("expect the unset option to have no source")
+	}
+
+	timestamp := time.Date(2021, 8, 25, 12, 0, 0, 0, time.UTC)
+	err := config.LoadDataSet(DataSet{
+		Data:      []byte(`{"opt1": "a", "opt2": 1}`),
+		Format:    "json",
+		Source:    "file:/path/to/file",
+		Checksum:  "md5:123",
+		Timestamp: timestamp,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = config.Set("opt2", 2)
+
+	expect := OptSource{Source: "file:/path/to/file", Layer: "file:/path/to/file", Checksum: "md5:123", Timestamp: timestamp}
+	if source, ok := config.GetOptSource("opt1"); !ok {
+		t.Errorf("expect the source of the option '%s'", "opt1")
+	} else if source != expect {
+		t.Errorf("expect the source '%+v', but got '%+v'", expect, source)
+	}
+
+	if source, _ := config.GetOptSource("opt2"); source.Source != "runtime" || source.Timestamp.IsZero() {
+		t.Errorf("unexpected the source '%+v'", source)
+	}
+
+	if len(observed) != 3 {
+		t.Errorf("expect %d observed sources, but got %d", 3, len(observed))
+	} else if observed[2].Source != "runtime" {
+		t.Errorf("expect the observed source '%s', but got '%s'", "runtime", observed[2].Source)
+	}
+
+	_, snap := config.SnapshotWithSources()
+	if len(snap) != 2 {
+		t.Errorf("expect %d snapshot elements, but got %d", 2, len(snap))
+	} else if v := snap["opt1"]; v.Value != "a" || v.OptSource != expect {
+		t.Errorf("unexpected the snapshot of the option '%s': %+v", "opt1", v)
+	}
+}
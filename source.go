@@ -30,6 +30,15 @@ type DataSet struct {
 	Source    string    // Such as "file:/path/to/file", "zk:127.0.0.1:2181", etc.
 	Checksum  string    // Such as "md5:7d2f31e6fff478337478413ee1b70d2a", etc.
 	Timestamp time.Time // The timestamp when the data is modified.
+
+	// NotModified reports whether the data is not modified since the last
+	// reading, such as the response "304 Not Modified" of the url source,
+	// which is different from the empty data.
+	NotModified bool
+
+	// values is the decoded option values, such as the merged values
+	// of the dir source, which is loaded instead of decoding Data.
+	values map[string]interface{}
 }
 
 // Md5 returns the md5 checksum of the DataSet data
@@ -53,31 +62,73 @@ func LoadDataSet(ds DataSet, force ...bool) error {
 // If failing to parse the value of any option, it terminates to parse
 // and load it.
 //
+// The options are loaded into the layer of the source of ds, see SourceLayer,
+// which replaces all the option values loaded from the same source before.
+// So the option removed from the source, or all the options if the data
+// is empty, such as the file is truncated or removed, fall back to the next
+// layer. And it does nothing if ds.NotModified is true.
+//
+// If the source of ds is empty, the options are loaded into the layer
+// LayerRuntime like LoadMap instead, and the empty data is ignored.
 // If force is missing or false, ignore the assigned options.
+//
+// If the arguments of ds are not nil, they are set into Config.Args
+// only if Config.Args is nil or force is true.
+//
+// The source, checksum and timestamp of ds are recorded as the source
+// of the loaded options, which can be got by GetOptSource.
 func (c *Config) LoadDataSet(ds DataSet, force ...bool) (err error) {
-	if len(ds.Data) == 0 {
-		return nil
+	_force := len(force) > 0 && force[0]
+	if ds.Source != "" {
+		err = c.loadDataSetLayer(SourceLayer(ds.Source), ds)
+	} else if !ds.NotModified && len(ds.Data) > 0 {
+		var ms map[string]interface{}
+		if ms, err = c.decodeDataSet(ds); err == nil {
+			err = c.loadMap(ms, _force, newOptSource(ds.Source, ds.Checksum, ds.Timestamp))
+		}
 	}
 
-	decoder := c.GetDecoder(ds.Format)
-	if decoder == nil {
-		return ErrNoDecoder
+	if err == nil && ds.Args != nil && (c.Args == nil || _force) {
+		c.Args = ds.Args
 	}
+	return
+}
 
-	ms := make(map[string]interface{}, 32)
-	if err = decoder(ds.Data, ms); err != nil {
-		return err
+// decodeDataSet decodes the data of ds into a map,
+// which is empty if the data is empty.
+func (c *Config) decodeDataSet(ds DataSet) (ms map[string]interface{}, err error) {
+	if ds.values != nil {
+		return ds.values, nil
 	}
 
-	if err = c.LoadMap(ms, force...); err == nil && ds.Args != nil {
-		if c.Args == nil || (len(force) > 0 && force[0]) {
-			c.Args = ds.Args
-		}
+	ms = make(map[string]interface{}, 32)
+	if len(ds.Data) == 0 {
+		return
 	}
 
+	decoder := c.GetDecoder(ds.Format)
+	if decoder == nil {
+		return nil, ErrNoDecoder
+	}
+
+	err = decoder(ds.Data, ms)
 	return
 }
 
+// configSource is the source which decodes the data by the decoders of Config,
+// which is bound to the Config loading it.
+type configSource interface {
+	withConfig(c *Config) Source
+}
+
+// bindSource binds the source to c if it decodes the data by Config.
+func (c *Config) bindSource(source Source) Source {
+	if s, ok := source.(configSource); ok {
+		return s.withConfig(c)
+	}
+	return source
+}
+
 // Source represents a data source where the data is.
 type Source interface {
 	// String is the description of the source, such as "env", "file:/path/to".
@@ -101,18 +152,49 @@ func LoadSource(source Source, force ...bool) error {
 //
 // If force is missing or false, ignore the assigned options.
 func (c *Config) LoadSource(source Source, force ...bool) (err error) {
-	ds, err := source.Read()
-	if err != nil {
+	_, err = c.loadSource(c.bindSource(source), func(ds DataSet) error {
+		return c.LoadDataSet(ds, force...)
+	})
+	return
+}
+
+// loadSource reads the data from the source and loads it by load,
+// then returns the loaded DataSet.
+func (c *Config) loadSource(source Source, load func(DataSet) error) (ds DataSet, err error) {
+	if ds, err = source.Read(); err != nil {
 		c.errorf("fail to read the source '%s': %s", source.String(), err)
-		return
+	} else if err = load(ds); err != nil {
+		c.errorf("fail to load the source '%s': %s", source.String(), err)
 	}
+	return
+}
 
-	if err = c.LoadDataSet(ds, force...); err != nil {
-		c.errorf("fail to load the source '%s': %s", source.String(), err)
-		return
+// sinceWatcher is the source which can watch the change since the loaded
+// DataSet, so that the change between reading the data to load and starting
+// to watch is not missed.
+type sinceWatcher interface {
+	watchSince(ds DataSet, exit <-chan struct{}, load func(DataSet, error) bool)
+}
+
+// watchSource watches the source since the loaded DataSet ds,
+// and loads the changed data by load until c is stopped.
+func (c *Config) watchSource(source Source, ds DataSet, load func(DataSet) error) {
+	callback := func(ds DataSet, err error) bool {
+		if err != nil {
+			c.errorf("fail to watch the source '%s': %s", source, err)
+			return false
+		} else if err = load(ds); err != nil {
+			c.errorf("fail to load the source '%s': %s", source, err)
+			return false
+		}
+		return true
 	}
 
-	return
+	if watcher, ok := source.(sinceWatcher); ok {
+		watcher.watchSince(ds, c.exit, callback)
+	} else {
+		source.Watch(c.exit, callback)
+	}
 }
 
 // LoadAndWatchSource is equal to Conf.LoadAndWatchSource(source, force...).
@@ -123,17 +205,10 @@ func LoadAndWatchSource(source Source, force ...bool) error {
 // LoadAndWatchSource is the same as LoadSource, but also watches the source
 // after loading the source successfully.
 func (c *Config) LoadAndWatchSource(source Source, force ...bool) (err error) {
-	if err = c.LoadSource(source, force...); err == nil {
-		go source.Watch(c.exit, func(ds DataSet, err error) bool {
-			if err != nil {
-				c.errorf("fail to watch the source '%s': %s", source, err)
-				return false
-			} else if err = c.LoadDataSet(ds, true); err != nil {
-				c.errorf("fail to load the source '%s': %s", source, err)
-				return false
-			}
-			return true
-		})
+	source = c.bindSource(source)
+	ds, err := c.loadSource(source, func(ds DataSet) error { return c.LoadDataSet(ds, force...) })
+	if err == nil {
+		go c.watchSource(source, ds, func(ds DataSet) error { return c.LoadDataSet(ds, true) })
 	}
 	return
 }
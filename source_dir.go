@@ -0,0 +1,207 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// NewDirSource is equal to Conf.NewDirSource(dir, pattern).
+func NewDirSource(dir, pattern string) Source {
+	return Conf.NewDirSource(dir, pattern)
+}
+
+// NewDirSource returns a new source that the data is read from all the files
+// in the directory dir matching the shell file name pattern, such as "*.yaml",
+// which is "*" by default. It is used to merge the conf.d-style fragments.
+//
+// The files are read in the lexical order of the filename, each of which
+// is decoded by the decoder identified by the filename extension, then they
+// are merged into one DataSet in the format "json", that's, the later file
+// overrides the option values of the former. The decoders are those of
+// the Config which loads the source, such as by LoadSource, or c if the source
+// is read directly. And the merged values are loaded as they are decoded
+// without decoding the json data again, so the large integers are not rounded.
+//
+// The dir source can watch the change of the directory, including that
+// the file is added, removed or changed. On Linux, it watches the directory
+// by inotify, and also polls it every 10s to follow the file which is
+// a symlink to the other directory. On the other platforms, or if inotify
+// fails, it only polls the directory every 10s.
+func (c *Config) NewDirSource(dir, pattern string) Source {
+	if pattern == "" {
+		pattern = "*"
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		panic(fmt.Errorf("invalid dir source pattern '%s': %s", pattern, err))
+	}
+
+	return dirSource{
+		id:       fmt.Sprintf("dir:%s", filepath.Join(dir, pattern)),
+		dir:      dir,
+		pattern:  pattern,
+		config:   c,
+		timeout:  time.Second * 10,
+		debounce: time.Millisecond * 100,
+	}
+}
+
+type dirSource struct {
+	id       string
+	dir      string
+	pattern  string
+	config   *Config
+	timeout  time.Duration
+	debounce time.Duration
+}
+
+func (d dirSource) String() string { return d.id }
+
+func (d dirSource) withConfig(c *Config) Source {
+	d.config = c
+	return d
+}
+
+// files returns the sorted regular files in the directory matching the pattern.
+func (d dirSource) files() ([]string, error) {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return nil, err
+	}
+
+	files := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if matched, _ := filepath.Match(d.pattern, entry.Name()); !matched {
+			continue
+		}
+
+		// Follow the symlink to check whether it is a regular file.
+		path := filepath.Join(d.dir, entry.Name())
+		if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() {
+			files = append(files, path)
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func (d dirSource) Read() (DataSet, error) {
+	files, err := d.files()
+	if err != nil {
+		return DataSet{Source: d.id, Format: "json"}, err
+	}
+
+	var timestamp time.Time
+	options := make(map[string]interface{}, 32)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return DataSet{Source: d.id, Format: "json"}, err
+		} else if fi, err := os.Stat(file); err == nil && fi.ModTime().After(timestamp) {
+			timestamp = fi.ModTime()
+		}
+
+		if len(data) == 0 {
+			continue
+		}
+
+		format := strings.Trim(filepath.Ext(file), ".")
+		decoder := d.config.GetDecoder(format)
+		if decoder == nil {
+			return DataSet{Source: d.id, Format: "json"},
+				fmt.Errorf("%w for the file '%s'", ErrNoDecoder, file)
+		}
+
+		ms := make(map[string]interface{}, 32)
+		if err = decoder(data, ms); err != nil {
+			return DataSet{Source: d.id, Format: "json"},
+				fmt.Errorf("fail to decode the file '%s': %w", file, err)
+		}
+
+		for name, value := range d.config.flatMap(ms) {
+			options[d.config.fixOptionName(name)] = value
+		}
+	}
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		return DataSet{Source: d.id, Format: "json"}, err
+	}
+
+	ds := DataSet{
+		Data:      data,
+		Format:    "json",
+		Source:    d.id,
+		Timestamp: timestamp,
+		values:    options,
+	}
+	ds.Checksum = "md5:" + ds.Md5()
+	return ds, nil
+}
+
+func (d dirSource) Watch(exit <-chan struct{}, load func(DataSet, error) bool) {
+	d.watch(exit, load)
+}
+
+func (d dirSource) watch(exit <-chan struct{}, load func(DataSet, error) bool) {
+	var events <-chan struct{}
+	match := func(name string) bool {
+		matched, _ := filepath.Match(d.pattern, name)
+		return matched
+	}
+	if watcher, err := newDirWatcher(d.dir, match); err == nil {
+		defer watcher.Close()
+		events = watcher.Events()
+	}
+
+	last, _ := d.fingerprint()
+	reload := func() {
+		if fp, err := d.fingerprint(); err != nil {
+			load(DataSet{Source: d.id, Format: "json"}, err)
+		} else if fp != last {
+			load(d.Read())
+			last = fp
+		}
+	}
+
+	watchChanges(exit, events, d.timeout, d.debounce, reload, reload)
+}
+
+// fingerprint returns the fingerprint of the matched files, which changes
+// when any file is added, removed or changed.
+func (d dirSource) fingerprint() (string, error) {
+	files, err := d.files()
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, file := range files {
+		if fi, err := os.Stat(file); err == nil {
+			fmt.Fprintf(&b, "%s:%d:%d\n", file, fi.Size(), fi.ModTime().UnixNano())
+		}
+	}
+	return b.String(), nil
+}
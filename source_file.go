@@ -28,6 +28,47 @@ var ConfigFileOpt = StrOpt("config-file", "the config file path.")
 
 func init() { Conf.RegisterOpts(ConfigFileOpt) }
 
+// FileSourceOption is used to configure the file source.
+type FileSourceOption func(*fileSource)
+
+// FileDefaultFormat returns a file source option to set the default format,
+// which is used when the filename has no extension.
+//
+// Default: "ini"
+func FileDefaultFormat(format string) FileSourceOption {
+	return func(f *fileSource) {
+		if format != "" && f.format == "" {
+			f.format = format
+		}
+	}
+}
+
+// FileWatchInterval returns a file source option to set the interval
+// to poll the change of the file, which is also used as the fallback
+// when the event-driven watcher is available, such as inotify on Linux.
+//
+// Default: 10s
+func FileWatchInterval(interval time.Duration) FileSourceOption {
+	return func(f *fileSource) {
+		if interval > 0 {
+			f.timeout = interval
+		}
+	}
+}
+
+// FileWatchDebounce returns a file source option to set the debounce
+// duration, during which the burst change events of the file are merged
+// into one reloading.
+//
+// Default: 100ms
+func FileWatchDebounce(debounce time.Duration) FileSourceOption {
+	return func(f *fileSource) {
+		if debounce > 0 {
+			f.debounce = debounce
+		}
+	}
+}
+
 // NewFileSource returns a new source that the data is read from the file
 // named filename.
 //
@@ -35,17 +76,37 @@ func init() { Conf.RegisterOpts(ConfigFileOpt) }
 // And it will identify the format by the filename extension automatically.
 // If no filename extension, it will use defaulFormat, which is "ini" by default.
 func NewFileSource(filename string, defaultFormat ...string) Source {
-	format := strings.Trim(filepath.Ext(filename), ".")
-	if format == "" {
-		if len(defaultFormat) > 0 && defaultFormat[0] != "" {
-			format = defaultFormat[0]
-		} else {
-			format = "ini"
-		}
+	if len(defaultFormat) > 0 {
+		return NewFileSourceWithOptions(filename, FileDefaultFormat(defaultFormat[0]))
+	}
+	return NewFileSourceWithOptions(filename)
+}
+
+// NewFileSourceWithOptions is the same as NewFileSource, but configures
+// the file source with the options.
+//
+// On Linux, the file source watches the change of the file by inotify,
+// which monitors the parent directory so that it can follow the editors
+// saving the file atomically by renaming a temporary file over it.
+// On the other platforms, or if inotify fails, it falls back to polling.
+func NewFileSourceWithOptions(filename string, options ...FileSourceOption) Source {
+	f := fileSource{
+		id:       fmt.Sprintf("file:%s", filename),
+		format:   strings.Trim(filepath.Ext(filename), "."),
+		filepath: filename,
+		timeout:  time.Second * 10,
+		debounce: time.Millisecond * 100,
+	}
+
+	for _, option := range options {
+		option(&f)
+	}
+
+	if f.format == "" {
+		f.format = "ini"
 	}
 
-	id := fmt.Sprintf("file:%s", filename)
-	return fileSource{id: id, filepath: filename, format: format, timeout: time.Second * 10}
+	return f
 }
 
 type fileSource struct {
@@ -53,6 +114,7 @@ type fileSource struct {
 	format   string
 	filepath string
 	timeout  time.Duration
+	debounce time.Duration
 }
 
 func (f fileSource) String() string { return f.id }
@@ -89,39 +151,101 @@ func (f fileSource) Read() (DataSet, error) {
 }
 
 func (f fileSource) Watch(exit <-chan struct{}, load func(DataSet, error) bool) {
-	f.watch(exit, load)
+	ds, _ := f.Read()
+	f.watchSince(ds, exit, load)
 }
 
-func (f fileSource) watch(exit <-chan struct{}, load func(DataSet, error) bool) {
-	lastsize, lasttime, _ := getfileinfo(f.filepath)
+// watchSince watches the change of the file since the loaded DataSet ds,
+// which is used as the baseline, instead of reading the file again.
+func (f fileSource) watchSince(ds DataSet, exit <-chan struct{}, load func(DataSet, error) bool) {
+	var events <-chan struct{}
+	if watcher, err := newFileWatcher(f.filepath); err == nil {
+		defer watcher.Close()
+		events = watcher.Events()
+	}
+
+	var lastinfo os.FileInfo
+	lastsum := ds.Checksum
+	reload := func() {
+		// If the file is removed, load the empty data once
+		// to let the options fall back to the next layer.
+		info, err := os.Stat(f.filepath)
+		if err != nil && !os.IsNotExist(err) {
+			load(DataSet{Source: f.id, Format: f.format}, err)
+			return
+		}
+		lastinfo = info
+
+		ds, err := f.Read()
+		if err != nil {
+			load(ds, err)
+		} else if ds.Checksum != lastsum {
+			lastsum = ds.Checksum
+			load(ds, nil)
+		}
+	}
+
+	// Reload the file which may be changed after ds is read.
+	reload()
+	watchChanges(exit, events, f.timeout, f.debounce, reload, func() {
+		if info, err := os.Stat(f.filepath); err != nil {
+			if !os.IsNotExist(err) {
+				load(DataSet{Source: f.id, Format: f.format}, err)
+			} else if lastinfo != nil {
+				reload()
+			}
+		} else if isFileChanged(lastinfo, info) {
+			reload()
+		}
+	})
+}
 
-	ticker := time.NewTicker(f.timeout)
+// watchChanges calls reload after the burst events are merged during debounce,
+// and calls poll every interval, until exit is closed.
+func watchChanges(exit <-chan struct{}, events <-chan struct{},
+	interval, debounce time.Duration, reload, poll func()) {
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	timer := time.NewTimer(debounce)
+	defer timer.Stop()
+	if !timer.Stop() {
+		<-timer.C
+	}
+
 	for {
 		select {
 		case <-exit:
 			return
 
-		case <-ticker.C:
-			if size, time, err := getfileinfo(f.filepath); err != nil {
-				if !os.IsNotExist(err) {
-					load(DataSet{Source: f.id, Format: f.format}, err)
+		case <-events:
+			// Merge the burst events, such as CREATE, MODIFY and CLOSE_WRITE.
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
 				}
-			} else if size != lastsize || time != lasttime {
-				load(f.Read())
-				lastsize = size
-				lasttime = time
 			}
+			timer.Reset(debounce)
+
+		case <-timer.C:
+			reload()
+
+		case <-ticker.C:
+			poll()
 		}
 	}
 }
 
-func getfileinfo(filepath string) (size, time int64, err error) {
-	fi, err := os.Stat(filepath)
-	if err == nil {
-		time = fi.ModTime().Unix()
-		size = fi.Size()
-	}
-	return
+// isFileChanged reports whether the file has been changed, including
+// that it is replaced by another file.
+func isFileChanged(last, now os.FileInfo) bool {
+	return last == nil || !os.SameFile(last, now) || last.Size() != now.Size() ||
+		!last.ModTime().Equal(now.ModTime())
+}
+
+// fileWatcher is used to watch the change events of the file.
+type fileWatcher interface {
+	Events() <-chan struct{}
+	Close() error
 }
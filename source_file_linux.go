@@ -0,0 +1,101 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package gconf
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_CREATE | syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE |
+	syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM | syscall.IN_DELETE | syscall.IN_ATTRIB
+
+// inotifyWatcher watches the directory by inotify, so it can follow the file
+// that is removed, created or renamed over.
+type inotifyWatcher struct {
+	file   *os.File
+	match  func(name string) bool
+	events chan struct{}
+}
+
+func newFileWatcher(filename string) (fileWatcher, error) {
+	dir, name := filepath.Split(filepath.Clean(filename))
+	if dir == "" {
+		dir = "."
+	}
+	return newDirWatcher(dir, func(n string) bool { return n == name })
+}
+
+func newDirWatcher(dir string, match func(name string) bool) (fileWatcher, error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, os.NewSyscallError("inotify_init1", err)
+	}
+
+	if _, err = syscall.InotifyAddWatch(fd, dir, inotifyMask); err != nil {
+		syscall.Close(fd)
+		return nil, os.NewSyscallError("inotify_add_watch", err)
+	}
+
+	// The non-blocking fd is added into the runtime poller,
+	// so Close can interrupt the blocking Read.
+	w := &inotifyWatcher{
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		match:  match,
+		events: make(chan struct{}, 1),
+	}
+	go w.loop()
+	return w, nil
+}
+
+func (w *inotifyWatcher) Events() <-chan struct{} { return w.events }
+func (w *inotifyWatcher) Close() error            { return w.file.Close() }
+
+func (w *inotifyWatcher) loop() {
+	buf := make([]byte, (syscall.SizeofInotifyEvent+syscall.NAME_MAX+1)*16)
+	for {
+		n, err := w.file.Read(buf)
+		if err != nil {
+			return
+		}
+
+		var matched bool
+		for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+			event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			start := offset + syscall.SizeofInotifyEvent
+			offset = start + int(event.Len)
+			if offset > n {
+				break
+			}
+
+			name := string(bytes.TrimRight(buf[start:offset], "\x00"))
+			if event.Mask&syscall.IN_Q_OVERFLOW != 0 || w.match(name) {
+				matched = true
+			}
+		}
+
+		if matched {
+			select {
+			case w.events <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
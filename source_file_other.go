@@ -0,0 +1,33 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+
+package gconf
+
+import "errors"
+
+var errNoFileWatcher = errors.New("no event-driven file watcher")
+
+// newFileWatcher always returns an error, so the file source falls back
+// to polling.
+func newFileWatcher(filename string) (fileWatcher, error) {
+	return nil, errNoFileWatcher
+}
+
+// newDirWatcher always returns an error, so the dir source falls back
+// to polling.
+func newDirWatcher(dir string, match func(name string) bool) (fileWatcher, error) {
+	return nil, errNoFileWatcher
+}
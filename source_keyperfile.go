@@ -0,0 +1,187 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// k8sDataDir is the symlink to the real data directory of the Kubernetes
+// ConfigMap or Secret volume, which is swapped atomically when updating.
+const k8sDataDir = "..data"
+
+// NewKeyPerFileSource returns a new source that the data is read from
+// the directory dir, in which each filename is the option name, such as
+// "opt", "group.opt" or "group1.group2.opt", and the file content is
+// the raw option value, the trailing newlines of which will be trimmed.
+// The file whose name starts with "." is ignored.
+//
+// It is used to read the volume mounted by the Kubernetes ConfigMap or Secret.
+// If the directory contains the symlink "..data", all the keys are read
+// from the directory which it points to, so they are consistent even if
+// it is swapped during reading. And it will watch the swap of the symlink
+// "..data" and reload all the keys together.
+//
+// On Linux, it watches the directory by inotify, and also polls it every 10s.
+// On the other platforms, or if inotify fails, it only polls the directory
+// every 10s.
+func NewKeyPerFileSource(dir string) Source {
+	return keyPerFileSource{
+		id:       fmt.Sprintf("keyperfile:%s", dir),
+		dir:      dir,
+		timeout:  time.Second * 10,
+		debounce: time.Millisecond * 100,
+	}
+}
+
+type keyPerFileSource struct {
+	id       string
+	dir      string
+	timeout  time.Duration
+	debounce time.Duration
+}
+
+func (k keyPerFileSource) String() string { return k.id }
+
+// dataDir returns the real directory where the key files are.
+func (k keyPerFileSource) dataDir() (string, error) {
+	dir, err := filepath.EvalSymlinks(filepath.Join(k.dir, k8sDataDir))
+	switch {
+	case err == nil:
+		return dir, nil
+	case os.IsNotExist(err):
+		return k.dir, nil
+	default:
+		return "", err
+	}
+}
+
+func (k keyPerFileSource) Read() (DataSet, error) {
+	dir, err := k.dataDir()
+	if err != nil {
+		return DataSet{Source: k.id, Format: "json"}, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return DataSet{Source: k.id, Format: "json"}, nil
+		}
+		return DataSet{Source: k.id, Format: "json"}, err
+	}
+
+	var timestamp time.Time
+	options := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		fi, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) { // Broken symlink
+				continue
+			}
+			return DataSet{Source: k.id, Format: "json"}, err
+		} else if !fi.Mode().IsRegular() {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return DataSet{Source: k.id, Format: "json"}, err
+		}
+
+		if fi.ModTime().After(timestamp) {
+			timestamp = fi.ModTime()
+		}
+		options[name] = strings.TrimRight(string(data), "\r\n")
+	}
+
+	data, err := json.Marshal(options)
+	if err != nil {
+		return DataSet{Source: k.id, Format: "json"}, err
+	}
+
+	ds := DataSet{
+		Data:      data,
+		Format:    "json",
+		Source:    k.id,
+		Timestamp: timestamp,
+	}
+	ds.Checksum = "md5:" + ds.Md5()
+	return ds, nil
+}
+
+func (k keyPerFileSource) Watch(exit <-chan struct{}, load func(DataSet, error) bool) {
+	k.watch(exit, load)
+}
+
+func (k keyPerFileSource) watch(exit <-chan struct{}, load func(DataSet, error) bool) {
+	var events <-chan struct{}
+	match := func(string) bool { return true } // Include the swap of "..data".
+	if watcher, err := newDirWatcher(k.dir, match); err == nil {
+		defer watcher.Close()
+		events = watcher.Events()
+	}
+
+	last, _ := k.fingerprint()
+	reload := func() {
+		if fp, err := k.fingerprint(); err != nil {
+			load(DataSet{Source: k.id, Format: "json"}, err)
+		} else if fp != last {
+			load(k.Read())
+			last = fp
+		}
+	}
+
+	watchChanges(exit, events, k.timeout, k.debounce, reload, reload)
+}
+
+// fingerprint returns the target of the symlink "..data" if it exists,
+// or the fingerprint of all the key files.
+func (k keyPerFileSource) fingerprint() (string, error) {
+	target, err := os.Readlink(filepath.Join(k.dir, k8sDataDir))
+	if err == nil {
+		return "link:" + target, nil
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+
+	entries, err := os.ReadDir(k.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, entry := range entries {
+		if name := entry.Name(); !strings.HasPrefix(name, ".") {
+			if fi, err := os.Stat(filepath.Join(k.dir, name)); err == nil {
+				fmt.Fprintf(&b, "%s:%d:%d\n", name, fi.Size(), fi.ModTime().UnixNano())
+			}
+		}
+	}
+	return b.String(), nil
+}
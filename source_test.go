@@ -15,8 +15,17 @@
 package gconf
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 )
@@ -143,6 +152,131 @@ func TestNewFileSource_JSON(t *testing.T) {
 	}
 }
 
+func TestNewFileSource_YAML(t *testing.T) {
+	// Prepare the yaml file
+	filename := "_test_yaml_file_source_.yaml"
+	file, err := os.OpenFile(filename, testfileflag, os.ModePerm)
+	if err != nil {
+		t.Error(err)
+	} else {
+		_, _ = file.Write([]byte(`
+opt1: 1
+group1:
+  opt2: true
+  group2:
+    opt3: 3
+    opt4: [1s, 2s]
+`))
+		file.Close()
+	}
+	defer os.Remove(filename)
+
+	// Load the config
+	conf := New()
+	conf.RegisterOpts(IntOpt("opt1", ""))
+	conf.Group("group1").RegisterOpts(BoolOpt("opt2", ""))
+	conf.Group("group1.group2").RegisterOpts(Float64Opt("opt3", ""))
+	conf.Group("group1.group2").RegisterOpts(DurationSliceOpt("opt4", ""))
+	if err := conf.LoadSource(NewFileSource(filename)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the config
+	if v := conf.GetInt("opt1"); v != 1 {
+		t.Error(v)
+	} else if v := conf.GetBool("group1.opt2"); !v {
+		t.Fail()
+	} else if v := conf.GetFloat64("group1.group2.opt3"); v != 3 {
+		t.Error(v)
+	} else if v := conf.GetDurationSlice("group1.group2.opt4"); len(v) != 2 || v[1] != time.Second*2 {
+		t.Error(v)
+	}
+}
+
+func TestNewFileSource_TOML(t *testing.T) {
+	// Prepare the toml file
+	filename := "_test_toml_file_source_.toml"
+	file, err := os.OpenFile(filename, testfileflag, os.ModePerm)
+	if err != nil {
+		t.Error(err)
+	} else {
+		_, _ = file.Write([]byte(`
+opt1 = 1
+
+[group1]
+opt2 = true
+
+[group1.group2]
+opt3 = 3
+opt4 = [1, 2, 3]
+opt5 = ["a", "b"]
+`))
+		file.Close()
+	}
+	defer os.Remove(filename)
+
+	// Load the config
+	conf := New()
+	conf.RegisterOpts(IntOpt("opt1", ""))
+	conf.Group("group1").RegisterOpts(BoolOpt("opt2", ""))
+	conf.Group("group1.group2").RegisterOpts(
+		Float64Opt("opt3", ""),
+		IntSliceOpt("opt4", ""),
+		StrSliceOpt("opt5", ""),
+	)
+	if err := conf.LoadSource(NewFileSource(filename)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the config
+	if v := conf.GetInt("opt1"); v != 1 {
+		t.Error(v)
+	} else if v := conf.GetBool("group1.opt2"); !v {
+		t.Fail()
+	} else if v := conf.GetFloat64("group1.group2.opt3"); v != 3 {
+		t.Error(v)
+	} else if v := conf.GetIntSlice("group1.group2.opt4"); len(v) != 3 || v[2] != 3 {
+		t.Error(v)
+	} else if v := conf.GetStringSlice("group1.group2.opt5"); len(v) != 2 || v[1] != "b" {
+		t.Error(v)
+	}
+}
+
+func TestNewFileSource_YAMLNull(t *testing.T) {
+	// Prepare the yaml file with the null values.
+	filename := "_test_yaml_null_file_source_.yaml"
+	file, err := os.OpenFile(filename, testfileflag, os.ModePerm)
+	if err != nil {
+		t.Error(err)
+	} else {
+		_, _ = file.Write([]byte(`
+opt1:
+opt2: abc
+group1:
+  opt3:
+`))
+		file.Close()
+	}
+	defer os.Remove(filename)
+
+	// Load the config
+	conf := New()
+	conf.RegisterOpts(IntOpt("opt1", "").D(1), StrOpt("opt2", ""))
+	conf.Group("group1").RegisterOpts(BoolOpt("opt3", ""))
+	if err := conf.LoadSource(NewFileSource(filename)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Check the config
+	if conf.OptIsSet("opt1") || conf.OptIsSet("group1.opt3") {
+		t.Error("the option with the null value is set")
+	} else if v := conf.GetInt("opt1"); v != 1 {
+		t.Error(v)
+	} else if v := conf.GetString("opt2"); v != "abc" {
+		t.Error(v)
+	}
+}
+
 func TestFileSourceWatch(t *testing.T) {
 	// Prepare the json file
 	filename := "_test_file_source_watch_.json"
@@ -188,6 +322,111 @@ func TestFileSourceWatch(t *testing.T) {
 	}
 }
 
+func TestFileSourceWatchSince(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.json")
+	if err := os.WriteFile(filename, []byte(`{"opt": 1}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	source := NewFileSourceWithOptions(filename, FileWatchInterval(time.Minute)).(fileSource)
+	ds, err := source.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The file is changed after it is read to load and before watching it.
+	if err = os.WriteFile(filename, []byte(`{"opt": 2}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	exit := make(chan struct{})
+	defer close(exit)
+
+	loaded := make(chan string, 1)
+	go source.watchSince(ds, exit, func(ds DataSet, err error) bool {
+		if err != nil {
+			t.Error(err)
+		} else {
+			select {
+			case loaded <- string(ds.Data):
+			case <-exit:
+			}
+		}
+		return true
+	})
+
+	select {
+	case data := <-loaded:
+		if expect := `{"opt": 2}`; data != expect {
+			t.Errorf("expect '%s', but got '%s'", expect, data)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("not load the change before watching")
+	}
+}
+
+func TestFileSourceWatch_Event(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the event-driven file watcher is only supported on linux")
+	}
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.json")
+	writeFile := func(data string) { // Save the file atomically like the editors.
+		tmpfile := filepath.Join(dir, ".app.json.swp")
+		if err := os.WriteFile(tmpfile, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		} else if err = os.Rename(tmpfile, filename); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(`{"opt": 1}`)
+
+	source := NewFileSourceWithOptions(filename,
+		FileWatchInterval(time.Minute), FileWatchDebounce(time.Millisecond*20))
+
+	exit := make(chan struct{})
+	defer close(exit)
+
+	loaded := make(chan string, 4)
+	go source.Watch(exit, func(ds DataSet, err error) bool {
+		if err != nil {
+			t.Error(err)
+		} else {
+			loaded <- string(ds.Data)
+		}
+		return true
+	})
+
+	// The new data has the same size and is written within the same second.
+	for _, expect := range []string{`{"opt": 2}`, `{"opt": 3}`} {
+		time.Sleep(time.Millisecond * 50)
+		writeFile(expect)
+
+		select {
+		case data := <-loaded:
+			if data != expect {
+				t.Errorf("expect '%s', but got '%s'", expect, data)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("not watch the change of the file")
+		}
+	}
+
+	// Load the empty data once the file is removed.
+	if err := os.Remove(filename); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case data := <-loaded:
+		if data != "" {
+			t.Errorf("expect the empty data, but got '%s'", data)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("not watch the removal of the file")
+	}
+}
+
 func TestNewURLSource(t *testing.T) {
 	first := true
 
@@ -221,3 +460,477 @@ func TestNewURLSource(t *testing.T) {
 		}
 	}
 }
+
+func TestNewDirSource(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("00-base.yaml", "opt1: 1\ngroup:\n  opt2: a\n  opt3: x\n")
+	writeFile("10-override.yaml", "group:\n  opt2: b\n")
+	writeFile("20-ignored.json", `{"opt1": 2}`)
+
+	conf := New()
+	conf.RegisterOpts(IntOpt("opt1", ""))
+	conf.Group("group").RegisterOpts(StrOpt("opt2", ""), StrOpt("opt3", ""))
+
+	source := conf.NewDirSource(dir, "*.yaml").(dirSource)
+	if err := conf.LoadSource(source); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := conf.GetInt("opt1"); v != 1 {
+		t.Errorf("expect '%d', but got '%d'", 1, v)
+	} else if v := conf.GetString("group.opt2"); v != "b" {
+		t.Errorf("expect '%s', but got '%s'", "b", v)
+	} else if v := conf.GetString("group.opt3"); v != "x" {
+		t.Errorf("expect '%s', but got '%s'", "x", v)
+	}
+
+	// Only poll the directory without the event-driven watcher.
+	if source.timeout = time.Minute; runtime.GOOS != "linux" {
+		source.timeout = time.Millisecond * 50
+	}
+	exit := make(chan struct{})
+	defer close(exit)
+
+	loaded := make(chan DataSet, 1)
+	go source.Watch(exit, func(ds DataSet, err error) bool {
+		if err != nil {
+			t.Error(err)
+		} else {
+			select {
+			case loaded <- ds:
+			case <-exit:
+			}
+		}
+		return true
+	})
+
+	time.Sleep(time.Millisecond * 100)
+	writeFile("30-added.yaml", "group:\n  opt3: y\n")
+
+	select {
+	case ds := <-loaded:
+		if err := conf.LoadDataSet(ds, true); err != nil {
+			t.Error(err)
+		} else if v := conf.GetString("group.opt3"); v != "y" {
+			t.Errorf("expect '%s', but got '%s'", "y", v)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("not watch the added file")
+	}
+}
+
+func TestDirSourceDecoders(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "00-base.yaml"), []byte("big: 9007199254740993\n"), 0600); err != nil {
+		t.Fatal(err)
+	} else if err = os.WriteFile(filepath.Join(dir, "10-app.conf"), []byte("name=abc"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// The source created by Conf uses the decoders of the Config loading it.
+	conf := New()
+	conf.RegisterOpts(Int64Opt("big", ""), StrOpt("name", ""))
+	conf.AddDecoder("conf", conf.GetDecoder("ini"))
+	if err := conf.LoadSource(NewDirSource(dir, "")); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := conf.GetInt64("big"); v != 9007199254740993 {
+		t.Errorf("expect '%d', but got '%d'", int64(9007199254740993), v)
+	} else if v := conf.GetString("name"); v != "abc" {
+		t.Errorf("expect '%s', but got '%s'", "abc", v)
+	}
+}
+
+func TestNewKeyPerFileSource(t *testing.T) {
+	// Simulate the layout of the Kubernetes ConfigMap volume.
+	dir := t.TempDir()
+	writeData := func(version string, kvs map[string]string) {
+		datadir := filepath.Join(dir, version)
+		if err := os.Mkdir(datadir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		for key, value := range kvs {
+			if err := os.WriteFile(filepath.Join(datadir, key), []byte(value), 0600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// Swap the symlink "..data" atomically.
+		tmplink := filepath.Join(dir, "..data_tmp")
+		if err := os.Symlink(version, tmplink); err != nil {
+			t.Fatal(err)
+		} else if err := os.Rename(tmplink, filepath.Join(dir, "..data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeData("..v1", map[string]string{"opt1": "1\n", "group.opt2": "a"})
+	for _, key := range []string{"opt1", "group.opt2"} {
+		if err := os.Symlink(filepath.Join("..data", key), filepath.Join(dir, key)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conf := New()
+	conf.RegisterOpts(IntOpt("opt1", ""))
+	conf.Group("group").RegisterOpts(StrOpt("opt2", ""))
+
+	source := NewKeyPerFileSource(dir).(keyPerFileSource)
+	if err := conf.LoadSource(source); err != nil {
+		t.Fatal(err)
+	} else if v := conf.GetInt("opt1"); v != 1 {
+		t.Errorf("expect '%d', but got '%d'", 1, v)
+	} else if v := conf.GetString("group.opt2"); v != "a" {
+		t.Errorf("expect '%s', but got '%s'", "a", v)
+	}
+
+	// Only poll the directory without the event-driven watcher.
+	if source.timeout = time.Minute; runtime.GOOS != "linux" {
+		source.timeout = time.Millisecond * 50
+	}
+	exit := make(chan struct{})
+	defer close(exit)
+
+	loaded := make(chan DataSet, 1)
+	go source.Watch(exit, func(ds DataSet, err error) bool {
+		if err != nil {
+			t.Error(err)
+		} else {
+			select {
+			case loaded <- ds:
+			case <-exit:
+			}
+		}
+		return true
+	})
+
+	time.Sleep(time.Millisecond * 100)
+	writeData("..v2", map[string]string{"opt1": "2\n", "group.opt2": "b"})
+
+	select {
+	case ds := <-loaded:
+		if err := conf.LoadDataSet(ds, true); err != nil {
+			t.Error(err)
+		} else if v := conf.GetInt("opt1"); v != 2 {
+			t.Errorf("expect '%d', but got '%d'", 2, v)
+		} else if v := conf.GetString("group.opt2"); v != "b" {
+			t.Errorf("expect '%s', but got '%s'", "b", v)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("not watch the swap of the symlink '..data'")
+	}
+}
+
+func TestURLSourceConditionalRequest(t *testing.T) {
+	lastmod := time.Date(2021, 8, 25, 12, 0, 0, 0, time.UTC)
+	var notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Cache-Control", "public, max-age=30")
+		w.Header().Set("Last-Modified", lastmod.Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"opt": 123}`))
+	}))
+	defer server.Close()
+
+	source := NewURLSource(server.URL, time.Minute).(*urlSource)
+	state := new(urlState)
+	ds, err := source.read(context.Background(), 0, state)
+	if err != nil {
+		t.Fatal(err)
+	} else if string(ds.Data) != `{"opt": 123}` {
+		t.Errorf("unexpected data '%s'", ds.Data)
+	} else if !ds.Timestamp.Equal(lastmod) {
+		t.Errorf("expect the timestamp '%s', but got '%s'", lastmod, ds.Timestamp)
+	} else if interval := source.nextInterval(state); interval != time.Second*30 {
+		t.Errorf("expect the interval '%s', but got '%s'", time.Second*30, interval)
+	}
+
+	if ds, err = source.read(context.Background(), 0, state); err != nil {
+		t.Fatal(err)
+	} else if !ds.NotModified || len(ds.Data) != 0 {
+		t.Errorf("expect the not modified data, but got '%s'", ds.Data)
+	} else if notModified != 1 {
+		t.Errorf("expect %d not modified response, but got %d", 1, notModified)
+	}
+
+	// Read always sends the unconditional request.
+	for i := 0; i < 2; i++ {
+		if ds, err = source.Read(); err != nil {
+			t.Fatal(err)
+		} else if string(ds.Data) != `{"opt": 123}` {
+			t.Errorf("unexpected data '%s'", ds.Data)
+		}
+	}
+	if notModified != 1 {
+		t.Errorf("expect %d not modified response, but got %d", 1, notModified)
+	}
+}
+
+func TestNewURLSourceWithOptions(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		} else if r.Header.Get("X-App") != "demo" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		} else if r.URL.Path == "/hang" {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(`{"opt": 123}`))
+	}))
+	defer server.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	options := []URLSourceOption{
+		URLRootCAs(pool),
+		URLFormat("json"),
+		URLBearerToken("token"),
+		URLHeader("X-App", "demo"),
+		URLTimeout(time.Millisecond * 100),
+	}
+
+	ds, err := NewURLSourceWithOptions(server.URL, options...).Read()
+	if err != nil {
+		t.Fatal(err)
+	} else if string(ds.Data) != `{"opt": 123}` {
+		t.Errorf("unexpected data '%s'", ds.Data)
+	}
+
+	start := time.Now()
+	if _, err = NewURLSourceWithOptions(server.URL+"/hang", options...).Read(); err == nil {
+		t.Errorf("expect a timeout error")
+	} else if cost := time.Since(start); cost > time.Second {
+		t.Errorf("the request is not timeout in time: %s", cost)
+	}
+
+	if _, err = NewURLSourceWithOptions(server.URL, options[:2]...).Read(); err == nil {
+		t.Errorf("expect an unauthorized error")
+	}
+}
+
+func TestURLSourceTLSConfigMerge(t *testing.T) {
+	cert1 := tls.Certificate{Certificate: [][]byte{[]byte("cert1")}}
+	cert2 := tls.Certificate{Certificate: [][]byte{[]byte("cert2")}}
+	pool := x509.NewCertPool()
+
+	check := func(name string, options ...URLSourceOption) {
+		u := new(urlSource)
+		for _, option := range options {
+			option(u)
+		}
+
+		switch {
+		case u.tlsConfig == nil:
+			t.Errorf("%s: missing the tls config", name)
+		case u.tlsConfig.ServerName != "example.com":
+			t.Errorf("%s: unexpected server name '%s'", name, u.tlsConfig.ServerName)
+		case u.tlsConfig.RootCAs != pool:
+			t.Errorf("%s: the root CAs are lost", name)
+		case len(u.tlsConfig.Certificates) != 2:
+			t.Errorf("%s: expect 2 client certs, but got %d", name, len(u.tlsConfig.Certificates))
+		}
+	}
+
+	config := &tls.Config{ServerName: "example.com", Certificates: []tls.Certificate{cert2}}
+	check("before", URLClientCert(cert1), URLRootCAs(pool), URLTLSConfig(config))
+	check("after", URLTLSConfig(config), URLClientCert(cert1), URLRootCAs(pool))
+
+	if len(config.Certificates) != 1 || config.RootCAs != nil {
+		t.Errorf("the given tls config is modified")
+	}
+}
+
+func TestURLSourceWatchLongPoll(t *testing.T) {
+	var lock sync.Mutex
+	version, changed := 1, make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("wait") != "" {
+			lock.Lock()
+			ch, etag := changed, fmt.Sprintf(`"v%d"`, version)
+			lock.Unlock()
+
+			if r.Header.Get("If-None-Match") == etag {
+				select {
+				case <-ch:
+				case <-r.Context().Done():
+					return
+				}
+			}
+		}
+
+		lock.Lock()
+		etag, data := fmt.Sprintf(`"v%d"`, version), fmt.Sprintf(`{"opt": %d}`, version)
+		lock.Unlock()
+
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(data))
+	}))
+	defer server.Close()
+
+	source := NewURLSourceWithOptions(server.URL, URLWatch(URLWatchLongPoll),
+		URLInterval(time.Hour), URLLongPollWait(time.Second*10))
+	if ds, err := source.Read(); err != nil {
+		t.Fatal(err)
+	} else if string(ds.Data) != `{"opt": 1}` {
+		t.Fatalf("unexpected data '%s'", ds.Data)
+	}
+
+	exit := make(chan struct{})
+	defer close(exit)
+
+	datas := make(chan string, 4)
+	go source.Watch(exit, func(ds DataSet, err error) bool {
+		if err != nil {
+			t.Error(err)
+		} else {
+			datas <- string(ds.Data)
+		}
+		return true
+	})
+
+	// The watch loop starts with the unconditional request.
+	select {
+	case data := <-datas:
+		if data != `{"opt": 1}` {
+			t.Errorf("unexpected data '%s'", data)
+		}
+	case <-time.After(time.Second * 3):
+		t.Fatal("timeout to wait for the first response")
+	}
+
+	time.Sleep(time.Millisecond * 100)
+	lock.Lock()
+	version++
+	close(changed)
+	changed = make(chan struct{})
+	lock.Unlock()
+
+	select {
+	case data := <-datas:
+		if data != `{"opt": 2}` {
+			t.Errorf("unexpected data '%s'", data)
+		}
+	case <-time.After(time.Second * 3):
+		t.Fatal("timeout to wait for the long-polling response")
+	}
+}
+
+func TestURLSourceWatchSSE(t *testing.T) {
+	lastEventIDs := make(chan string, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			w.WriteHeader(http.StatusNotAcceptable)
+			return
+		}
+
+		lastEventID := r.Header.Get("Last-Event-ID")
+		lastEventIDs <- lastEventID
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, ": heartbeat\n\n")
+		if lastEventID != "" { // Hold the reconnected stream.
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+
+		// Close the stream after sending the events to reconnect.
+		_, _ = io.WriteString(w, "id: 1\ndata: {\"opt\":\ndata: 1}\n\n")
+		_, _ = io.WriteString(w, "event: other\ndata: ignored\n\n")
+		_, _ = io.WriteString(w, "id: 2\nevent: config\ndata: {\"opt\": 2}\n\n")
+	}))
+	defer server.Close()
+
+	exit := make(chan struct{})
+	defer close(exit)
+
+	datas := make(chan string, 4)
+	source := NewURLSourceWithOptions(server.URL, URLWatch(URLWatchSSE)).(*urlSource)
+	go source.Watch(exit, func(ds DataSet, err error) bool {
+		if err != nil {
+			t.Error(err)
+		} else if ds.Format != "json" {
+			t.Errorf("expect the format '%s', but got '%s'", "json", ds.Format)
+		} else {
+			datas <- string(ds.Data)
+		}
+		return true
+	})
+
+	for _, expect := range []string{"{\"opt\":\n1}", `{"opt": 2}`} {
+		select {
+		case data := <-datas:
+			if data != expect {
+				t.Errorf("expect the data '%s', but got '%s'", expect, data)
+			}
+		case <-time.After(time.Second * 3):
+			t.Fatal("timeout to wait for the event")
+		}
+	}
+
+	for _, expect := range []string{"", "2"} {
+		select {
+		case lastEventID := <-lastEventIDs:
+			if lastEventID != expect {
+				t.Errorf("expect the last event id '%s', but got '%s'", expect, lastEventID)
+			}
+		case <-time.After(time.Second * 3):
+			t.Fatal("timeout to wait for the connection")
+		}
+	}
+}
+
+func TestURLSourceWatchSSE_Fallback(t *testing.T) {
+	lastmod := time.Date(2021, 8, 25, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Last-Modified", lastmod.Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"opt": 123}`))
+	}))
+	defer server.Close()
+
+	exit := make(chan struct{})
+	defer close(exit)
+
+	loaded := make(chan DataSet, 1)
+	source := NewURLSourceWithOptions(server.URL, URLWatch(URLWatchSSE))
+	go source.Watch(exit, func(ds DataSet, err error) bool {
+		if err != nil {
+			t.Error(err)
+		} else {
+			select {
+			case loaded <- ds:
+			case <-exit:
+			}
+		}
+		return true
+	})
+
+	select {
+	case ds := <-loaded:
+		if string(ds.Data) != `{"opt": 123}` {
+			t.Errorf("unexpected data '%s'", ds.Data)
+		} else if !ds.Timestamp.Equal(lastmod) {
+			t.Errorf("expect the timestamp '%s', but got '%s'", lastmod, ds.Timestamp)
+		}
+	case <-time.After(time.Second * 3):
+		t.Fatal("timeout to wait for the data")
+	}
+}
@@ -15,18 +15,240 @@
 package gconf
 
 import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"net/http"
 	neturl "net/url"
+	"strconv"
 	"strings"
 	"time"
 )
 
 var errNoContentType = fmt.Errorf("http response has no the header Content-Type")
 
+const (
+	urlMinBackoff   = time.Millisecond * 500
+	urlMaxEventSize = 16 * 1024 * 1024
+)
+
+// URLWatchMode is the mode how the url source watches the change
+// of the configuration data.
+type URLWatchMode int
+
+const (
+	// URLWatchPoll polls the configuration data each interval.
+	URLWatchPoll URLWatchMode = iota
+
+	// URLWatchLongPoll uses the long-polling protocol, that's, the request
+	// carries the version token of the last response by the header
+	// "If-None-Match" and the query argument "wait" in seconds, then the server
+	// holds the request until the version token is changed, and responds
+	// the new configuration data with the new version token by the header
+	// "ETag", or responds "304 Not Modified" after waiting for "wait" seconds.
+	// Then send the next long-polling request immediately.
+	//
+	// If the server responds without holding the request, it is considered
+	// not to support the long-polling and polls each interval instead.
+	URLWatchLongPoll
+
+	// URLWatchSSE uses the stream of the Server-Sent Events, that's,
+	// the request carries the header "Accept: text/event-stream", and
+	// the data of each event whose type is "message" or "config" is
+	// the full configuration data. The event id is sent back by the header
+	// "Last-Event-ID" when reconnecting.
+	//
+	// If the server does not respond the event stream, the response body is
+	// used as the configuration data and polls each interval instead.
+	URLWatchSSE
+)
+
+// String returns the name of the watch mode.
+func (m URLWatchMode) String() string {
+	switch m {
+	case URLWatchPoll:
+		return "poll"
+	case URLWatchLongPoll:
+		return "longpoll"
+	case URLWatchSSE:
+		return "sse"
+	default:
+		return fmt.Sprintf("URLWatchMode(%d)", int(m))
+	}
+}
+
+// URLSourceOption is used to configure the url source.
+type URLSourceOption func(*urlSource)
+
+// URLInterval returns a url source option to set the interval to poll
+// the configuration data from the url.
+//
+// Default: time.Minute
+func URLInterval(interval time.Duration) URLSourceOption {
+	return func(u *urlSource) {
+		if interval > 0 {
+			u.period = interval
+		}
+	}
+}
+
+// URLFormat returns a url source option to set the format of the data,
+// which overrides the format from the response header "Content-Type".
+func URLFormat(format string) URLSourceOption {
+	return func(u *urlSource) { u.format = format }
+}
+
+// URLTimeout returns a url source option to set the timeout of each request.
+//
+// Default: 30s
+func URLTimeout(timeout time.Duration) URLSourceOption {
+	return func(u *urlSource) { u.timeout = timeout }
+}
+
+// URLWatch returns a url source option to set the mode to watch
+// the change of the configuration data.
+//
+// For URLWatchLongPoll and URLWatchSSE, it reconnects to the server
+// with the exponential backoff between 500ms and the interval when failing.
+// And the http client should not have the timeout, or the stream will be
+// interrupted, which is controlled by the url source itself instead.
+//
+// Default: URLWatchPoll
+func URLWatch(mode URLWatchMode) URLSourceOption {
+	return func(u *urlSource) { u.mode = mode }
+}
+
+// URLLongPollWait returns a url source option to set the maximum duration
+// that the server holds the long-polling request, which is rounded to seconds.
+//
+// Default: time.Minute
+func URLLongPollWait(wait time.Duration) URLSourceOption {
+	return func(u *urlSource) {
+		if wait >= time.Second {
+			u.wait = wait
+		}
+	}
+}
+
+// URLHTTPClient returns a url source option to set the http client
+// to send the request.
+//
+// Default: http.DefaultClient
+func URLHTTPClient(client *http.Client) URLSourceOption {
+	return func(u *urlSource) { u.client = client }
+}
+
+// URLHeader returns a url source option to add the static request header.
+func URLHeader(key, value string) URLSourceOption {
+	return func(u *urlSource) {
+		u.hooks = append(u.hooks, func(r *http.Request) error {
+			r.Header.Add(key, value)
+			return nil
+		})
+	}
+}
+
+// URLHeaderFunc returns a url source option to set the request headers
+// by the callback function before sending each request, which may be used
+// to refresh the dynamic headers.
+func URLHeaderFunc(setHeader func(header http.Header) error) URLSourceOption {
+	return func(u *urlSource) {
+		u.hooks = append(u.hooks, func(r *http.Request) error {
+			return setHeader(r.Header)
+		})
+	}
+}
+
+// URLBasicAuth returns a url source option to set the basic authentication.
+func URLBasicAuth(username, password string) URLSourceOption {
+	return func(u *urlSource) {
+		u.hooks = append(u.hooks, func(r *http.Request) error {
+			r.SetBasicAuth(username, password)
+			return nil
+		})
+	}
+}
+
+// URLBearerToken returns a url source option to set the bearer token
+// of the header "Authorization".
+func URLBearerToken(token string) URLSourceOption {
+	return URLBearerTokenFunc(func() (string, error) { return token, nil })
+}
+
+// URLBearerTokenFunc is the same as URLBearerToken, but gets the token
+// by the callback function before sending each request.
+func URLBearerTokenFunc(getToken func() (string, error)) URLSourceOption {
+	return func(u *urlSource) {
+		u.hooks = append(u.hooks, func(r *http.Request) error {
+			token, err := getToken()
+			if err == nil {
+				r.Header.Set("Authorization", "Bearer "+token)
+			}
+			return err
+		})
+	}
+}
+
+// URLTLSConfig returns a url source option to set the TLS configuration,
+// such as the custom root CAs, the client certificates for mTLS, etc.
+//
+// The configuration is merged with the client certificates and the root CAs
+// given by URLClientCert and URLRootCAs, whatever the order of the options is,
+// that's, the client certificates are appended and the root CAs are kept
+// if the configuration does not set them.
+//
+// If the http client is set and its transport is not *http.Transport,
+// the TLS configuration is ignored.
+func URLTLSConfig(config *tls.Config) URLSourceOption {
+	return func(u *urlSource) {
+		if config == nil {
+			return
+		}
+
+		old := u.tlsConfig
+		u.tlsConfig = config.Clone()
+		if old != nil {
+			certs := make([]tls.Certificate, 0, len(old.Certificates)+len(config.Certificates))
+			certs = append(certs, old.Certificates...)
+			u.tlsConfig.Certificates = append(certs, config.Certificates...)
+			if u.tlsConfig.RootCAs == nil {
+				u.tlsConfig.RootCAs = old.RootCAs
+			}
+		}
+	}
+}
+
+// URLClientCert returns a url source option to append the client
+// certificates for mTLS, which may be loaded by tls.LoadX509KeyPair.
+func URLClientCert(certs ...tls.Certificate) URLSourceOption {
+	return func(u *urlSource) {
+		if u.tlsConfig == nil {
+			u.tlsConfig = &tls.Config{}
+		} else {
+			u.tlsConfig = u.tlsConfig.Clone()
+		}
+		u.tlsConfig.Certificates = append(u.tlsConfig.Certificates, certs...)
+	}
+}
+
+// URLRootCAs returns a url source option to set the root CAs
+// to verify the certificate of the server.
+func URLRootCAs(pool *x509.CertPool) URLSourceOption {
+	return func(u *urlSource) {
+		if u.tlsConfig == nil {
+			u.tlsConfig = &tls.Config{}
+		} else {
+			u.tlsConfig = u.tlsConfig.Clone()
+		}
+		u.tlsConfig.RootCAs = pool
+	}
+}
+
 // NewURLSource returns a url source to read the configuration data
-// from the url by the stdlib http.Get(url).
+// from the url by the stdlib http.DefaultClient.
 //
 // The header "Content-Type" indicates the data format, that's, it will split
 // the value by "/" and use the last part, such as "application/json" represents
@@ -34,64 +256,170 @@ var errNoContentType = fmt.Errorf("http response has no the header Content-Type"
 //
 // The url source can watch the configuration data from the url each interval
 // period. If interval is equal to 0, it is defaulted to time.Minute.
+// But if the response has the header "Cache-Control" with "max-age",
+// it is used as the interval to poll next time instead.
+//
+// When watching, the url source sends the conditional request by the headers
+// "ETag" and "Last-Modified" of the last response, and the response
+// "304 Not Modified" is treated as no change. The state of the conditional
+// request is owned by each watch loop, which starts with the unconditional
+// request, and Read always sends the unconditional request, so the source
+// can be loaded repeatedly, even by the different Configs.
+//
+// Use NewURLSourceWithOptions with URLWatch to watch the change
+// by the long-polling or Server-Sent Events instead of polling.
 func NewURLSource(url string, interval time.Duration, format ...string) Source {
+	options := []URLSourceOption{URLInterval(interval)}
+	if len(format) > 0 && format[0] != "" {
+		options = append(options, URLFormat(format[0]))
+	}
+	return NewURLSourceWithOptions(url, options...)
+}
+
+// NewURLSourceWithOptions is the same as NewURLSource, but configures
+// the url source with the options, such as the http client, the headers,
+// the authentication, the TLS and the timeout.
+func NewURLSourceWithOptions(url string, options ...URLSourceOption) Source {
 	if url == "" {
 		panic("the url must not be nil")
 	} else if _, err := neturl.Parse(url); err != nil {
 		panic(err)
 	}
 
-	var _format string
-	if len(format) > 0 && format[0] != "" {
-		_format = format[0]
+	u := &urlSource{
+		id:      fmt.Sprintf("url:%s", url),
+		url:     url,
+		period:  time.Minute,
+		timeout: time.Second * 30,
+		wait:    time.Minute,
 	}
 
-	if interval <= 0 {
-		interval = time.Minute
+	for _, option := range options {
+		option(u)
 	}
 
-	return urlSource{
-		id:     fmt.Sprintf("url:%s", url),
-		url:    url,
-		format: _format,
-		period: interval,
+	if u.client == nil {
+		u.client = http.DefaultClient
+	}
+
+	if u.tlsConfig != nil {
+		var transport *http.Transport
+		switch t := u.client.Transport.(type) {
+		case nil:
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			transport = t.Clone()
+		}
+
+		if transport != nil {
+			transport.TLSClientConfig = u.tlsConfig
+			client := *u.client
+			client.Transport = transport
+			u.client = &client
+		}
 	}
+
+	return u
 }
 
 type urlSource struct {
 	id  string
 	url string
 
-	format string
-	period time.Duration
+	format  string
+	period  time.Duration
+	timeout time.Duration
+	wait    time.Duration
+	mode    URLWatchMode
+
+	client    *http.Client
+	tlsConfig *tls.Config
+	hooks     []func(*http.Request) error
 }
 
-func (u urlSource) String() string { return u.id }
+// urlState is the state of the last response in the watch loop,
+// which is used by the conditional request.
+type urlState struct {
+	etag    string
+	lastmod string
+	maxAge  time.Duration
+	eventID string
+}
 
-func (u urlSource) Read() (DataSet, error) {
-	resp, err := http.Get(u.url)
-	if resp != nil {
-		defer resp.Body.Close()
+func (s *urlState) update(header http.Header, validators bool) {
+	if validators {
+		s.etag = header.Get("ETag")
+		s.lastmod = header.Get("Last-Modified")
+	}
+	s.maxAge = getMaxAge(header.Get("Cache-Control"))
+}
+
+func (u *urlSource) String() string { return u.id }
+
+// Read reads the full configuration data from the url.
+func (u *urlSource) Read() (DataSet, error) {
+	return u.read(context.Background(), 0, nil)
+}
+
+// read reads the configuration data from the url. If wait is greater than 0,
+// it is a long-polling request, which asks the server to hold the request
+// for at most wait duration until the data is changed.
+//
+// If state is not nil, it sends the conditional request with the headers
+// "If-None-Match" and "If-Modified-Since" by state, and updates state
+// by the response. If the server returns "304 Not Modified", the returned
+// DataSet has no data, which means no change.
+func (u *urlSource) read(ctx context.Context, wait time.Duration, state *urlState) (DataSet, error) {
+	if timeout := u.timeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout+wait)
+		defer cancel()
+	}
+
+	url := u.url
+	if wait > 0 {
+		url = addURLQuery(url, "wait", strconv.FormatInt(int64(wait/time.Second), 10))
 	}
 
+	req, err := u.newRequest(ctx, url)
 	if err != nil {
 		return DataSet{Source: u.id, Format: u.format}, err
 	}
 
-	format := u.format
-	if format == "" {
-		// Get the format from the header "Content-Type".
-		ct := strings.TrimSpace(resp.Header.Get("Content-Type"))
-		if index := strings.IndexByte(ct, ';'); index > 0 {
-			ct = strings.TrimSpace(ct[:index])
+	if state != nil {
+		if state.etag != "" {
+			req.Header.Set("If-None-Match", state.etag)
 		}
-		if index := strings.LastIndexByte(ct, '/'); index > 0 {
-			ct = ct[index+1:]
+		if state.lastmod != "" {
+			req.Header.Set("If-Modified-Since", state.lastmod)
 		}
-		if ct == "" {
-			return DataSet{Source: u.id}, errNoContentType
+	}
+
+	resp, err := u.client.Do(req)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return DataSet{Source: u.id, Format: u.format}, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		if state != nil {
+			state.update(resp.Header, false)
+			return DataSet{Source: u.id, Format: u.format, NotModified: true}, nil
 		}
-		format = ct
+		fallthrough
+	default:
+		return DataSet{Source: u.id, Format: u.format},
+			fmt.Errorf("unexpected http response status code %d", resp.StatusCode)
+	}
+
+	format, err := u.getFormat(resp.Header)
+	if err != nil {
+		return DataSet{Source: u.id}, err
 	}
 
 	// Read the body of the response.
@@ -99,24 +427,118 @@ func (u urlSource) Read() (DataSet, error) {
 	if err != nil {
 		return DataSet{Source: u.id, Format: format}, err
 	}
+	if state != nil {
+		state.update(resp.Header, true)
+	}
 
 	ds := DataSet{
 		Data:      data,
 		Format:    format,
 		Source:    u.id,
-		Timestamp: time.Now(),
+		Timestamp: getLastModified(resp.Header),
 	}
 	ds.Checksum = "md5:" + ds.Md5()
 	return ds, nil
 }
 
-func (u urlSource) Watch(exit <-chan struct{}, load func(DataSet, error) bool) {
-	u.watch(exit, load)
+func (u *urlSource) newRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, hook := range u.hooks {
+		if err = hook(req); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+// getFormat returns the configured format, or the format
+// from the response header "Content-Type".
+func (u *urlSource) getFormat(header http.Header) (string, error) {
+	if u.format != "" {
+		return u.format, nil
+	}
+
+	ct := getMediaType(header)
+	if index := strings.LastIndexByte(ct, '/'); index > 0 {
+		ct = ct[index+1:]
+	}
+	if ct == "" {
+		return "", errNoContentType
+	}
+	return ct, nil
+}
+
+// getMediaType returns the media type of the header "Content-Type"
+// without the parameters, such as "application/json".
+func getMediaType(header http.Header) string {
+	ct := strings.TrimSpace(header.Get("Content-Type"))
+	if index := strings.IndexByte(ct, ';'); index > 0 {
+		ct = strings.TrimSpace(ct[:index])
+	}
+	return ct
+}
+
+// addURLQuery adds the query argument into the url.
+func addURLQuery(url, key, value string) string {
+	sep := "?"
+	if strings.IndexByte(url, '?') > -1 {
+		sep = "&"
+	}
+	return url + sep + neturl.QueryEscape(key) + "=" + neturl.QueryEscape(value)
+}
+
+// getLastModified returns the time of the header "Last-Modified",
+// or the current time if missing.
+func getLastModified(header http.Header) time.Time {
+	if lastmod, err := http.ParseTime(header.Get("Last-Modified")); err == nil {
+		return lastmod
+	}
+	return time.Now()
+}
+
+// getMaxAge returns the value of the directive "max-age" in the header
+// "Cache-Control", such as "public, max-age=60".
+func getMaxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		if strings.EqualFold(name, "max-age") {
+			if seconds, err := strconv.ParseUint(strings.Trim(value, `"`), 10, 32); err == nil {
+				return time.Duration(seconds) * time.Second
+			}
+			break
+		}
+	}
+	return 0
 }
 
-func (u urlSource) watch(exit <-chan struct{}, load func(DataSet, error) bool) {
-	ticker := time.NewTicker(u.period)
-	defer ticker.Stop()
+// nextInterval returns the interval to wait for the next poll, which is
+// the max-age of the last response if set, or the configured period.
+func (u *urlSource) nextInterval(state *urlState) time.Duration {
+	if state.maxAge > 0 {
+		return state.maxAge
+	}
+	return u.period
+}
+
+func (u *urlSource) Watch(exit <-chan struct{}, load func(DataSet, error) bool) {
+	switch u.mode {
+	case URLWatchLongPoll:
+		u.watchLongPoll(exit, load)
+	case URLWatchSSE:
+		u.watchSSE(exit, load)
+	default:
+		u.watch(exit, load)
+	}
+}
+
+func (u *urlSource) watch(exit <-chan struct{}, load func(DataSet, error) bool) {
+	state := new(urlState)
+	timer := time.NewTimer(u.nextInterval(state))
+	defer timer.Stop()
 
 	var last DataSet
 	for {
@@ -124,14 +546,236 @@ func (u urlSource) watch(exit <-chan struct{}, load func(DataSet, error) bool) {
 		case <-exit:
 			return
 
-		case <-ticker.C:
-			if ds, err := u.Read(); err != nil {
+		case <-timer.C:
+			if ds, err := u.read(context.Background(), 0, state); err != nil {
 				load(ds, err)
-			} else if len(ds.Data) > 0 && ds.Checksum != last.Checksum {
+			} else if !ds.NotModified && ds.Checksum != last.Checksum {
 				if load(ds, nil) {
 					last = ds
 				}
 			}
+			timer.Reset(u.nextInterval(state))
+		}
+	}
+}
+
+// exitContext returns a context which is cancelled when exit is closed.
+func exitContext(exit <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-exit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// sleep waits for the duration, and returns false if exit is closed.
+func sleep(exit <-chan struct{}, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-exit:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// backoff is the exponential backoff to reconnect to the server,
+// which starts from base and is doubled each time until max.
+type backoff struct {
+	base time.Duration
+	max  time.Duration
+	next time.Duration
+}
+
+func (b *backoff) Reset() { b.next = 0 }
+
+func (b *backoff) Next() time.Duration {
+	switch {
+	case b.next <= 0:
+		b.next = b.base
+	case b.next < b.max:
+		if b.next *= 2; b.next > b.max {
+			b.next = b.max
 		}
 	}
+	return b.next
+}
+
+func (u *urlSource) newBackoff() *backoff {
+	max := u.period
+	if max < urlMinBackoff {
+		max = urlMinBackoff
+	}
+	return &backoff{base: urlMinBackoff, max: max}
+}
+
+func (u *urlSource) watchLongPoll(exit <-chan struct{}, load func(DataSet, error) bool) {
+	ctx, cancel := exitContext(exit)
+	defer cancel()
+
+	var last DataSet
+	state := new(urlState)
+	backoff := u.newBackoff()
+	for {
+		start := time.Now()
+		ds, err := u.read(ctx, u.wait, state)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			load(ds, err)
+			if !sleep(exit, backoff.Next()) {
+				return
+			}
+			continue
+		}
+		backoff.Reset()
+
+		if !ds.NotModified && ds.Checksum != last.Checksum {
+			if load(ds, nil) {
+				last = ds
+			}
+		} else if time.Since(start) < u.wait/2 {
+			// The server does not hold the request until the data is changed,
+			// so it does not support long-polling. Fall back to polling.
+			if !sleep(exit, u.nextInterval(state)) {
+				return
+			}
+		}
+	}
+}
+
+func (u *urlSource) watchSSE(exit <-chan struct{}, load func(DataSet, error) bool) {
+	ctx, cancel := exitContext(exit)
+	defer cancel()
+
+	var last DataSet
+	state := new(urlState)
+	backoff := u.newBackoff()
+	for {
+		interval, err := u.readEvents(ctx, state, backoff, func(ds DataSet) {
+			if !ds.NotModified && ds.Checksum != last.Checksum {
+				if load(ds, nil) {
+					last = ds
+				}
+			}
+		})
+
+		if ctx.Err() != nil {
+			return
+		} else if err != nil {
+			load(DataSet{Source: u.id, Format: u.format}, err)
+		}
+
+		if interval <= 0 {
+			interval = backoff.Next()
+		}
+		if !sleep(exit, interval) {
+			return
+		}
+	}
+}
+
+// readEvents connects to the server and reads the Server-Sent Events
+// until the stream is closed, each of which has the full configuration data.
+//
+// If the server does not respond the event stream, the response body is
+// loaded as the configuration data, and return the interval to poll it next.
+func (u *urlSource) readEvents(ctx context.Context, state *urlState, backoff *backoff,
+	load func(DataSet)) (interval time.Duration, err error) {
+	req, err := u.newRequest(ctx, u.url)
+	if err != nil {
+		return
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if state.eventID != "" {
+		req.Header.Set("Last-Event-ID", state.eventID)
+	}
+
+	resp, err := u.client.Do(req)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+
+	if err != nil {
+		return
+	} else if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("unexpected http response status code %d", resp.StatusCode)
+		return
+	}
+
+	if getMediaType(resp.Header) != "text/event-stream" {
+		// The server does not support SSE. Fall back to polling.
+		var ds DataSet
+		if ds.Format, err = u.getFormat(resp.Header); err != nil {
+			return
+		} else if ds.Data, err = io.ReadAll(resp.Body); err != nil {
+			return
+		}
+
+		backoff.Reset()
+		state.update(resp.Header, true)
+		ds.Source = u.id
+		ds.Timestamp = getLastModified(resp.Header)
+		ds.Checksum = "md5:" + ds.Md5()
+		load(ds)
+		return u.nextInterval(state), nil
+	}
+
+	format := u.format
+	if format == "" {
+		format = "json"
+	}
+
+	var data []string
+	var event string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 4096), urlMaxEventSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" { // Dispatch the event.
+			if len(data) > 0 && (event == "" || event == "message" || event == "config") {
+				backoff.Reset()
+				ds := DataSet{
+					Data:      []byte(strings.Join(data, "\n")),
+					Format:    format,
+					Source:    u.id,
+					Timestamp: time.Now(),
+				}
+				ds.Checksum = "md5:" + ds.Md5()
+				load(ds)
+			}
+			data, event = data[:0], ""
+			continue
+		} else if line[0] == ':' { // Comment, such as the heartbeat.
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "data":
+			data = append(data, value)
+		case "event":
+			event = value
+		case "id":
+			state.eventID = value
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 32); err == nil && ms > 0 {
+				backoff.base = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+
+	err = scanner.Err()
+	return
 }
@@ -0,0 +1,366 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// structOpt is the option defined by the struct field.
+type structOpt struct {
+	opt   Opt
+	field reflect.Value
+}
+
+// RegisterStruct is equal to Conf.RegisterStruct(ptr).
+func RegisterStruct(ptr interface{}) { Conf.RegisterStruct(ptr) }
+
+// RegisterStruct registers the options defined by the fields of the struct
+// which ptr points to, the field of which supports the tags as follow:
+//
+//	gconf:"name,short=p,cli,alias=name2"  // "-" means to ignore the field.
+//	default:"8080"                        // The default value, which is parsed by the option.
+//	help:"the help information"
+//	validate:"port"                       // The validators joined by the comma, see RegisterValidator.
+//
+// The validators in the tag "validate" are split only at the comma followed
+// by a registered validator name, so the argument may contain the comma,
+// such as `validate:"nonempty,regexp=^[0-9]{1,3}$"`.
+//
+// If the name is missing, it is the field name in snake case, such as
+// "max_conns" for the field MaxConns. The option is not a cli option unless
+// the tag "cli" is given. And if the tag "default" is missing, the current
+// value of the field is used as the default value.
+//
+// The field of the nested struct, or the pointer to struct which will be
+// allocated if nil, is registered as the option group. But the embedded
+// struct without the name is flattened into the current group.
+//
+// The supported field types are bool, string, int, int16, int32, int64,
+// uint, uint16, uint32, uint64, float64, time.Duration, time.Time, []string,
+// []int, []uint, []float64 and []time.Duration, or the named types of them,
+// such as "type Mode string" or "type Tags []string".
+//
+// Notice: it will panic if ptr is not a pointer to struct, or any field
+// or tag is invalid, or any option has existed.
+func (c *Config) RegisterStruct(ptr interface{}) { c.registerStruct("", ptr) }
+
+// RegisterStruct is the same as Config.RegisterStruct,
+// but registers the options into the group.
+func (g *OptGroup) RegisterStruct(ptr interface{}) { g.config.registerStruct(g.prefix, ptr) }
+
+func (c *Config) registerStruct(prefix string, ptr interface{}) {
+	sopts := c.getStructOpts(prefix, getStructValue(ptr))
+	opts := make([]Opt, len(sopts))
+	for i, sopt := range sopts {
+		opts[i] = sopt.opt
+	}
+	c.RegisterOpts(opts...)
+}
+
+// BindStruct is equal to Conf.BindStruct(ptr).
+func BindStruct(ptr interface{}) (cancel func()) { return Conf.BindStruct(ptr) }
+
+// BindStruct binds the fields of the struct which ptr points to
+// to the options registered by RegisterStruct with the same struct type,
+// that's, the fields are set to the current values of the options,
+// and will be updated each time the option value is changed until
+// the returned function is called to unbind them.
+//
+// Notice:
+//  1. It will panic if any option does not exist or its type does not match.
+//  2. The fields are updated in the goroutine which changes the option value,
+//     such as the watcher of the source, and always end at the latest values
+//     of the options even if the options are changed concurrently. But the
+//     caller should synchronize the reading of the fields, or use Watch instead.
+func (c *Config) BindStruct(ptr interface{}) (cancel func()) { return c.bindStruct("", ptr) }
+
+// BindStruct is the same as Config.BindStruct,
+// but binds the options in the group.
+func (g *OptGroup) BindStruct(ptr interface{}) (cancel func()) {
+	return g.config.bindStruct(g.prefix, ptr)
+}
+
+func (c *Config) bindStruct(prefix string, ptr interface{}) (cancel func()) {
+	type boundField struct {
+		field  reflect.Value
+		option *option
+	}
+
+	fields := c.getStructFields(prefix, getStructValue(ptr))
+	bounds := make(map[string]boundField, len(fields))
+	for name, field := range fields {
+		option, ok := c.getOption(name)
+		if !ok {
+			panic(fmt.Errorf("no option named '%s'", name))
+		}
+
+		setStructField(name, field, option.Get())
+		bounds[option.Opt().Name] = boundField{field: field, option: option}
+	}
+
+	// The notifications of the concurrent changes may be delivered
+	// out of order, so always set the field to the current value
+	// of the option instead of the notified new value, which makes
+	// the field end at the latest value.
+	var lock sync.Mutex
+	return c.ObserveWithSource(func(name string, _, _ interface{}, _ OptSource) {
+		if bound, ok := bounds[name]; ok {
+			lock.Lock()
+			defer lock.Unlock()
+			setStructField(name, bound.field, bound.option.Get())
+		}
+	})
+}
+
+func getStructValue(ptr interface{}) reflect.Value {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		panic(fmt.Errorf("expect a pointer to struct, but got %T", ptr))
+	}
+	return v.Elem()
+}
+
+func setStructField(name string, field reflect.Value, value interface{}) {
+	v := reflect.ValueOf(value)
+	switch {
+	case v.Type().AssignableTo(field.Type()):
+	case v.Type().ConvertibleTo(field.Type()):
+		v = v.Convert(field.Type())
+	default:
+		panic(fmt.Errorf("the value of the option named '%s' is typed %T, not %s",
+			name, value, field.Type()))
+	}
+	field.Set(v)
+}
+
+func (c *Config) getStructOpts(prefix string, v reflect.Value) (opts []structOpt) {
+	c.walkStruct(prefix, v, func(prefix, name string, sf reflect.StructField,
+		field reflect.Value, args string) {
+		opt, err := newStructFieldOpt(prefix, name, sf, field, args)
+		if err != nil {
+			panic(fmt.Errorf("invalid struct field '%s.%s': %w", v.Type().Name(), sf.Name, err))
+		}
+		opts = append(opts, structOpt{opt: opt, field: field})
+	})
+	return
+}
+
+// getStructFields returns the fields of the struct v by the option names.
+func (c *Config) getStructFields(prefix string, v reflect.Value) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value, 16)
+	c.walkStruct(prefix, v, func(prefix, name string, _ reflect.StructField,
+		field reflect.Value, _ string) {
+		fields[prefix+name] = field
+	})
+	return fields
+}
+
+// walkStruct walks the fields of the struct v which are the options,
+// and allocates the nil pointer to the nested struct.
+func (c *Config) walkStruct(prefix string, v reflect.Value, walk func(prefix, name string,
+	sf reflect.StructField, field reflect.Value, args string)) {
+	vtype := v.Type()
+	for i, _len := 0, v.NumField(); i < _len; i++ {
+		sf := vtype.Field(i)
+		if !sf.IsExported() && !(sf.Anonymous && sf.Type.Kind() == reflect.Struct) {
+			continue
+		}
+
+		tag := sf.Tag.Get("gconf")
+		if tag == "-" {
+			continue
+		}
+
+		name, args, _ := strings.Cut(tag, ",")
+		if name = strings.TrimSpace(name); name == "" {
+			name = toSnakeCase(sf.Name)
+		}
+
+		field := v.Field(i)
+		if field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct &&
+			field.Type().Elem() != timeType {
+			if field.IsNil() {
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			field = field.Elem()
+		}
+
+		if field.Kind() == reflect.Struct && field.Type() != timeType {
+			if sf.Anonymous && tag == "" {
+				c.walkStruct(prefix, field, walk)
+			} else {
+				c.walkStruct(prefix+name+c.gsep, field, walk)
+			}
+			continue
+		}
+
+		walk(prefix, name, sf, field, args)
+	}
+}
+
+func newStructFieldOpt(prefix, name string, sf reflect.StructField,
+	field reflect.Value, args string) (opt Opt, err error) {
+	if opt, err = newOptByType(prefix+name, sf.Tag.Get("help"), field.Type()); err != nil {
+		return
+	}
+	opt.IsCli = false
+
+	for _, arg := range strings.Split(args, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(arg), "=")
+		switch key {
+		case "":
+		case "cli":
+			opt.IsCli = true
+		case "short":
+			if len(value) != 1 {
+				return opt, fmt.Errorf("the short name '%s' is not a single character", value)
+			}
+			opt.Short = value
+		case "alias":
+			if value == "" {
+				return opt, fmt.Errorf("the alias must not be empty")
+			}
+			opt.Aliases = append(opt.Aliases, prefix+value)
+		default:
+			return opt, fmt.Errorf("unknown tag argument '%s'", key)
+		}
+	}
+
+	var _default interface{}
+	if s, ok := sf.Tag.Lookup("default"); ok {
+		_default = s
+	} else if t := reflect.TypeOf(opt.Default); field.Type() != t && field.Type().ConvertibleTo(t) {
+		_default = field.Convert(t).Interface() // Such as the named type.
+	} else {
+		_default = field.Interface()
+	}
+
+	if _default, err = opt.Parser(_default); err != nil {
+		return opt, fmt.Errorf("invalid default value: %w", err)
+	}
+	opt.Default = _default
+
+	if validate := strings.TrimSpace(sf.Tag.Get("validate")); validate != "" {
+		for _, s := range splitValidateTag(validate) {
+			vname, varg, _ := strings.Cut(strings.TrimSpace(s), "=")
+			validator, err := GetValidator(vname, varg)
+			if err != nil {
+				return opt, err
+			}
+			opt = opt.DV(validator)
+		}
+	}
+
+	return
+}
+
+// splitValidateTag splits the tag "validate" at the comma followed by
+// a registered validator name, and the comma not followed by it is a part
+// of the argument of the previous validator.
+func splitValidateTag(tag string) []string {
+	validatorLock.RLock()
+	defer validatorLock.RUnlock()
+
+	parts := strings.Split(tag, ",")
+	validates := make([]string, 0, len(parts))
+	for i, part := range parts {
+		name, _, _ := strings.Cut(strings.TrimSpace(part), "=")
+		if _, ok := validatorFactories[name]; ok || i == 0 {
+			validates = append(validates, part)
+		} else {
+			validates[len(validates)-1] += "," + part
+		}
+	}
+	return validates
+}
+
+func newOptByType(name, help string, t reflect.Type) (Opt, error) {
+	switch t {
+	case durationType:
+		return DurationOpt(name, help), nil
+	case timeType:
+		return TimeOpt(name, help), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return BoolOpt(name, help), nil
+	case reflect.String:
+		return StrOpt(name, help), nil
+	case reflect.Int:
+		return IntOpt(name, help), nil
+	case reflect.Int16:
+		return Int16Opt(name, help), nil
+	case reflect.Int32:
+		return Int32Opt(name, help), nil
+	case reflect.Int64:
+		return Int64Opt(name, help), nil
+	case reflect.Uint:
+		return UintOpt(name, help), nil
+	case reflect.Uint16:
+		return Uint16Opt(name, help), nil
+	case reflect.Uint32:
+		return Uint32Opt(name, help), nil
+	case reflect.Uint64:
+		return Uint64Opt(name, help), nil
+	case reflect.Float64:
+		return Float64Opt(name, help), nil
+	case reflect.Slice:
+		switch t.Elem() {
+		case durationType:
+			return DurationSliceOpt(name, help), nil
+		case reflect.TypeOf(""):
+			return StrSliceOpt(name, help), nil
+		case reflect.TypeOf(0):
+			return IntSliceOpt(name, help), nil
+		case reflect.TypeOf(uint(0)):
+			return UintSliceOpt(name, help), nil
+		case reflect.TypeOf(float64(0)):
+			return Float64SliceOpt(name, help), nil
+		}
+	}
+
+	return Opt{}, fmt.Errorf("unsupported type %s", t)
+}
+
+// toSnakeCase converts the name in camel case to snake case,
+// such as "MaxConns" to "max_conns" and "HTTPPort" to "http_port".
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	b.Grow(len(name) + 4)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,227 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testMode string
+
+type testCommonConfig struct {
+	Debug bool `help:"Enable the debug mode."`
+}
+
+type testDBConfig struct {
+	Addrs    []string      `gconf:"addrs,alias=addr" default:"127.0.0.1:3306" validate:"addr_slice"`
+	MaxConns int           `default:"10" validate:"range=1:100"`
+	Timeout  time.Duration `default:"3s"`
+}
+
+type testServerConfig struct {
+	testCommonConfig
+
+	Port    int           `gconf:"port,short=p,cli" default:"8080" help:"The port." validate:"port"`
+	Mode    testMode      `validate:"oneof=dev|prod"`
+	HTTPTag string        `gconf:"-"`
+	DB      testDBConfig  `gconf:"database"`
+	Cache   *testDBConfig `help:"unused"`
+
+	private int
+}
+
+func TestConfigRegisterStruct(t *testing.T) {
+	c := New()
+	conf := testServerConfig{Mode: "dev"}
+	c.RegisterStruct(&conf)
+
+	opts := c.GetAllOpts()
+	names := make([]string, len(opts))
+	for i, opt := range opts {
+		names[i] = opt.Name
+	}
+
+	expects := []string{
+		"cache.addrs", "cache.max_conns", "cache.timeout",
+		"database.addrs", "database.max_conns", "database.timeout",
+		"debug", "mode", "port",
+	}
+	if !reflect.DeepEqual(expects, names) {
+		t.Fatalf("expect the options %v, but got %v", expects, names)
+	}
+
+	if opt, _ := c.GetOpt("port"); !opt.IsCli || opt.Short != "p" || opt.Help != "The port." ||
+		opt.Default != 8080 || len(opt.Validators) != 1 {
+		t.Errorf("unexpected option: %+v", opt)
+	}
+	if opt, _ := c.GetOpt("mode"); opt.IsCli || opt.Default != "dev" {
+		t.Errorf("unexpected option: %+v", opt)
+	}
+	if opt, _ := c.GetOpt("database.addr"); opt.Name != "database.addrs" {
+		t.Errorf("unexpected option: %+v", opt)
+	}
+
+	if err := c.Set("port", 70000); err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+	if err := c.Set("mode", "test"); err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+	if err := c.Set("database.max_conns", 0); err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+
+	var bound testServerConfig
+	unbind := c.BindStruct(&bound)
+	if bound.Port != 8080 || bound.Mode != "dev" || bound.DB.MaxConns != 10 ||
+		bound.DB.Timeout != time.Second*3 || !reflect.DeepEqual(bound.DB.Addrs, []string{"127.0.0.1:3306"}) {
+		t.Errorf("unexpected bound struct: %+v", bound)
+	}
+
+	_ = c.Set("port", 9090)
+	_ = c.Set("mode", "prod")
+	_ = c.Set("debug", true)
+	_ = c.Set("database.addrs", "10.0.0.1:3306,10.0.0.2:3306")
+	if bound.Port != 9090 || bound.Mode != "prod" || !bound.Debug ||
+		!reflect.DeepEqual(bound.DB.Addrs, []string{"10.0.0.1:3306", "10.0.0.2:3306"}) {
+		t.Errorf("unexpected bound struct: %+v", bound)
+	}
+
+	unbind()
+	_ = c.Set("port", 7070)
+	if bound.Port != 9090 {
+		t.Errorf("expect the unbound port %d, but got %d", 9090, bound.Port)
+	}
+}
+
+func TestConfigBindStructConcurrently(t *testing.T) {
+	var config struct {
+		Port int `default:"80"`
+	}
+
+	c := New()
+	c.RegisterStruct(&config)
+	c.BindStruct(&config)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = c.Set("port", i*100+j)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if port := c.GetInt("port"); config.Port != port {
+		t.Errorf("expect the bound port %d, but got %d", port, config.Port)
+	}
+}
+
+func TestConfigRegisterStructInvalid(t *testing.T) {
+	tests := []interface{}{
+		testServerConfig{},
+		&struct{ Map map[string]string }{},
+		&struct {
+			Port int `validate:"noexist"`
+		}{},
+		&struct {
+			Port int `gconf:"port,unknown"`
+		}{},
+		&struct {
+			Port int `default:"abc"`
+		}{},
+	}
+
+	for i, test := range tests {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d: expect a panic, but got nil", i)
+				}
+			}()
+			New().RegisterStruct(test)
+		}()
+	}
+}
+
+func TestConfigRegisterStructValidateComma(t *testing.T) {
+	c := New()
+	c.RegisterStruct(&struct {
+		Code string `default:"123" validate:"nonempty,regexp=^[0-9]{1,3}$,strlen=1:3"`
+	}{})
+
+	if opt, ok := c.GetOpt("code"); !ok {
+		t.Fatalf("missing the option '%s'", "code")
+	} else if len(opt.Validators) != 3 {
+		t.Errorf("expect %d validators, but got %d", 3, len(opt.Validators))
+	} else if desc := opt.ValidatorDescs[1]; desc != "a string matching the regular expression '^[0-9]{1,3}$'" {
+		t.Errorf("unexpected the description '%s'", desc)
+	}
+
+	if err := c.Set("code", "1234"); err == nil {
+		t.Errorf("expect an error, but got nil")
+	} else if err = c.Set("code", "12"); err != nil {
+		t.Error(err)
+	}
+
+	for tag, expect := range map[string][]string{
+		"port":                   {"port"},
+		"port, nonempty":         {"port", " nonempty"},
+		"oneof=a|b,regexp=a,b":   {"oneof=a|b", "regexp=a,b"},
+		"regexp=^[0-9]{1,3}$,ip": {"regexp=^[0-9]{1,3}$", "ip"},
+	} {
+		if validates := splitValidateTag(tag); !reflect.DeepEqual(validates, expect) {
+			t.Errorf("%s: expect %q, but got %q", tag, expect, validates)
+		}
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	for name, expect := range map[string]string{
+		"Port":      "port",
+		"MaxConns":  "max_conns",
+		"HTTPPort":  "http_port",
+		"ServerURL": "server_url",
+	} {
+		if s := toSnakeCase(name); s != expect {
+			t.Errorf("expect '%s', but got '%s'", expect, s)
+		}
+	}
+}
+
+func ExampleConfig_RegisterStruct() {
+	type Config struct {
+		Addr    string        `default:"127.0.0.1:80" validate:"addr"`
+		Timeout time.Duration `default:"1s"`
+	}
+
+	var config Config
+	c := New()
+	c.Group("server").RegisterStruct(&config)
+	c.Group("server").BindStruct(&config)
+
+	_ = c.Set("server.timeout", "3s")
+	fmt.Println(config.Addr, config.Timeout)
+
+	// Output:
+	// 127.0.0.1:80 3s
+}
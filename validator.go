@@ -21,6 +21,8 @@ import (
 	"net/url"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 var (
@@ -55,6 +57,48 @@ var (
 // Validator is used to validate whether the option value is valid.
 type Validator func(value interface{}) error
 
+// DescribedValidator is a validator with the description of the valid value,
+// such as "an integer between 1 and 100", which is used to generate the documents.
+type DescribedValidator struct {
+	Description string
+	Validator   Validator
+}
+
+// NewValidator returns a new validator with the description of the valid value.
+func NewValidator(description string, validator Validator) DescribedValidator {
+	return DescribedValidator{Description: description, Validator: validator}
+}
+
+// describeOr is the same as Or, but also joins the descriptions by "or".
+func describeOr(validators ...DescribedValidator) DescribedValidator {
+	_validators, descs := splitDescribedValidators(validators)
+	return NewValidator(strings.Join(descs, " or "), Or(_validators...))
+}
+
+// describeStrSlice is the same as NewStrSliceValidator,
+// but also describes the validators of the element.
+func describeStrSlice(validators ...DescribedValidator) DescribedValidator {
+	var desc string
+	_validators, descs := splitDescribedValidators(validators)
+	if len(descs) > 0 {
+		desc = "each element is " + strings.Join(descs, " and ")
+	}
+	return NewValidator(desc, NewStrSliceValidator(_validators...))
+}
+
+// splitDescribedValidators returns the validators and the non-empty descriptions.
+func splitDescribedValidators(validators []DescribedValidator) ([]Validator, []string) {
+	_validators := make([]Validator, len(validators))
+	descs := make([]string, 0, len(validators))
+	for i, v := range validators {
+		_validators[i] = v.Validator
+		if v.Description != "" {
+			descs = append(descs, v.Description)
+		}
+	}
+	return _validators, descs
+}
+
 // Or returns a union validator, which returns nil only if a certain validator
 // returns nil or the error that the last validator returns.
 func Or(validators ...Validator) Validator {
@@ -354,3 +398,162 @@ func NewFloatRangeValidator(min, max float64) Validator {
 func NewPortValidator() Validator {
 	return NewIntegerRangeValidator(0, 65535)
 }
+
+// ValidatorFactory is used to create a validator with the description
+// by the argument, which may be empty, such as "1:100" for "range=1:100".
+type ValidatorFactory func(arg string) (DescribedValidator, error)
+
+var (
+	validatorLock      sync.RWMutex
+	validatorFactories = map[string]ValidatorFactory{
+		"port":     newValidatorFactory(describePort),
+		"nonempty": newValidatorFactory(describeStrNotEmpty),
+		"url":      newValidatorFactory(describeURL),
+		"ip":       newValidatorFactory(describeIP),
+		"email":    newValidatorFactory(describeEmail),
+		"addr":     newValidatorFactory(describeAddress),
+
+		"maybe_url":   newValidatorFactory(func() DescribedValidator { return describeOr(describeEmptyStr(), describeURL()) }),
+		"maybe_ip":    newValidatorFactory(func() DescribedValidator { return describeOr(describeEmptyStr(), describeIP()) }),
+		"maybe_email": newValidatorFactory(func() DescribedValidator { return describeOr(describeEmptyStr(), describeEmail()) }),
+		"maybe_addr":  newValidatorFactory(func() DescribedValidator { return describeOr(describeEmptyStr(), describeAddress()) }),
+		"url_slice":   newValidatorFactory(func() DescribedValidator { return describeStrSlice(describeURL()) }),
+		"ip_slice":    newValidatorFactory(func() DescribedValidator { return describeStrSlice(describeIP()) }),
+		"email_slice": newValidatorFactory(func() DescribedValidator { return describeStrSlice(describeEmail()) }),
+		"addr_slice":  newValidatorFactory(func() DescribedValidator { return describeStrSlice(describeAddress()) }),
+		"addr_or_ip":  newValidatorFactory(describeAddressOrIP),
+		"maybe_addr_or_ip": newValidatorFactory(func() DescribedValidator {
+			return describeOr(describeEmptyStr(), describeAddressOrIP())
+		}),
+
+		"range": func(arg string) (DescribedValidator, error) {
+			min, max, err := splitValidatorRange(arg, func(s string) (int64, error) {
+				return strconv.ParseInt(s, 10, 64)
+			})
+			return describeIntegerRange(min, max), err
+		},
+		"frange": func(arg string) (DescribedValidator, error) {
+			min, max, err := splitValidatorRange(arg, func(s string) (float64, error) {
+				return strconv.ParseFloat(s, 64)
+			})
+			desc := fmt.Sprintf("a float between %v and %v", min, max)
+			return NewValidator(desc, NewFloatRangeValidator(min, max)), err
+		},
+		"strlen": func(arg string) (DescribedValidator, error) {
+			min, max, err := splitValidatorRange(arg, strconv.Atoi)
+			desc := fmt.Sprintf("a string whose length is between %d and %d", min, max)
+			return NewValidator(desc, NewStrLenValidator(min, max)), err
+		},
+		"oneof": func(arg string) (DescribedValidator, error) {
+			if arg == "" {
+				return DescribedValidator{}, fmt.Errorf("missing the values like 'a|b|c'")
+			}
+			array := strings.Split(arg, "|")
+			return NewValidator(fmt.Sprintf("one of %v", array), NewStrArrayValidator(array)), nil
+		},
+		"regexp": func(arg string) (DescribedValidator, error) {
+			if _, err := regexp.Compile(arg); err != nil {
+				return DescribedValidator{}, err
+			}
+			desc := fmt.Sprintf("a string matching the regular expression '%s'", arg)
+			return NewValidator(desc, NewRegexpValidator(arg)), nil
+		},
+	}
+)
+
+func describeEmptyStr() DescribedValidator {
+	return NewValidator("an empty string", NewEmptyStrValidator())
+}
+
+func describeStrNotEmpty() DescribedValidator {
+	return NewValidator("a non-empty string", NewStrNotEmptyValidator())
+}
+
+func describeURL() DescribedValidator { return NewValidator("a URL", NewURLValidator()) }
+func describeIP() DescribedValidator  { return NewValidator("an IP", NewIPValidator()) }
+
+func describeEmail() DescribedValidator {
+	return NewValidator("an email", NewEmailValidator())
+}
+
+func describeAddress() DescribedValidator {
+	return NewValidator("an address like host:port", NewAddressValidator())
+}
+
+func describeAddressOrIP() DescribedValidator {
+	return describeOr(describeIP(), describeAddress())
+}
+
+func describePort() DescribedValidator { return describeIntegerRange(0, 65535) }
+
+func describeIntegerRange(min, max int64) DescribedValidator {
+	desc := fmt.Sprintf("an integer between %d and %d", min, max)
+	return NewValidator(desc, NewIntegerRangeValidator(min, max))
+}
+
+func newValidatorFactory(newValidator func() DescribedValidator) ValidatorFactory {
+	return func(arg string) (DescribedValidator, error) {
+		if arg != "" {
+			return DescribedValidator{}, fmt.Errorf("unexpected the argument '%s'", arg)
+		}
+		return newValidator(), nil
+	}
+}
+
+func splitValidatorRange[T any](arg string, parse func(string) (T, error)) (min, max T, err error) {
+	smin, smax, ok := strings.Cut(arg, ":")
+	if !ok {
+		err = fmt.Errorf("invalid range '%s', which should be like 'min:max'", arg)
+	} else if min, err = parse(strings.TrimSpace(smin)); err == nil {
+		max, err = parse(strings.TrimSpace(smax))
+	}
+	return
+}
+
+// RegisterValidator registers the validator factory with the name,
+// which will override it if it has been registered. The validator
+// is referred by the name in the struct tag "validate", such as
+// `validate:"name"` or `validate:"name=arg"`, and its description
+// is used to generate the documents by Config.WriteDocs.
+//
+// The builtin validators are as follow:
+//
+//	port, nonempty,
+//	url, maybe_url, url_slice,
+//	ip, maybe_ip, ip_slice,
+//	email, maybe_email, email_slice,
+//	addr, maybe_addr, addr_slice, addr_or_ip, maybe_addr_or_ip,
+//	range=MIN:MAX       // The integer range, such as "range=1:100".
+//	frange=MIN:MAX      // The float range, such as "frange=0:1.5".
+//	strlen=MIN:MAX      // The length range of the string, such as "strlen=1:32".
+//	oneof=V1|V2|...     // The string must be one of them, such as "oneof=tcp|udp".
+//	regexp=PATTERN      // The string must match the pattern.
+func RegisterValidator(name string, factory ValidatorFactory) {
+	if name == "" {
+		panic("the validator name must not be empty")
+	} else if factory == nil {
+		panic("the validator factory must not be nil")
+	}
+
+	validatorLock.Lock()
+	validatorFactories[name] = factory
+	validatorLock.Unlock()
+}
+
+// GetValidator returns a new validator with the description
+// by the registered name and the argument.
+func GetValidator(name, arg string) (DescribedValidator, error) {
+	validatorLock.RLock()
+	factory, ok := validatorFactories[name]
+	validatorLock.RUnlock()
+
+	if !ok {
+		return DescribedValidator{}, fmt.Errorf("no validator named '%s'", name)
+	}
+
+	validator, err := factory(arg)
+	if err != nil {
+		return DescribedValidator{}, fmt.Errorf("invalid validator '%s': %w", name, err)
+	}
+	return validator, nil
+}
@@ -14,7 +14,12 @@
 
 package gconf
 
-import "testing"
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
 
 func TestNewMaybeURLValidator(t *testing.T) {
 	validate := NewMaybeURLValidator()
@@ -78,3 +83,41 @@ func TestNewAddressOrIPSliceValidator(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestGetValidator(t *testing.T) {
+	for name, expect := range map[string]string{
+		"port":             "an integer between 0 and 65535",
+		"maybe_addr_or_ip": "an empty string or an IP or an address like host:port",
+		"url_slice":        "each element is a URL",
+		"range=1:100":      "an integer between 1 and 100",
+		"oneof=tcp|udp":    "one of [tcp udp]",
+	} {
+		vname, varg, _ := strings.Cut(name, "=")
+		if validator, err := GetValidator(vname, varg); err != nil {
+			t.Errorf("%s: %s", name, err)
+		} else if validator.Description != expect {
+			t.Errorf("%s: expect the description '%s', but got '%s'", name, expect, validator.Description)
+		}
+	}
+
+	validate := func(value interface{}) error { return nil }
+	if desc := describeStrSlice(NewValidator("", validate)).Description; desc != "" {
+		t.Errorf("unexpected the description '%s'", desc)
+	}
+
+	opt := IntOpt("opt", "").V(validate).DV(NewValidator("a positive integer", func(value interface{}) error {
+		if value.(int) <= 0 {
+			return errors.New("the integer is not positive")
+		}
+		return nil
+	}))
+	if !reflect.DeepEqual(opt.ValidatorDescs, []string{"a positive integer"}) {
+		t.Errorf("unexpected the descriptions %v", opt.ValidatorDescs)
+	} else if len(opt.Validators) != 2 {
+		t.Errorf("expect %d validators, but got %d", 2, len(opt.Validators))
+	} else if err := opt.validate(1); err != nil {
+		t.Error(err)
+	} else if err := opt.validate(0); err == nil {
+		t.Errorf("expect an error, but got nil")
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Watcher is the atomic snapshot of a group of options as the struct T,
+// which is rebuilt each time the values of the options are changed.
+type Watcher[T any] struct {
+	conf   *Config
+	prefix string
+	value  atomic.Pointer[T]
+	lock   sync.Mutex
+	cancel func()
+}
+
+// Watch returns a watcher of the options in the group as the struct T,
+// which must have been registered by RegisterStruct with the same struct
+// type, for example,
+//
+//	type DBConfig struct {
+//	    Host string `default:"127.0.0.1"`
+//	    Port int    `default:"3306"`
+//	}
+//
+//	Group("db").RegisterStruct(new(DBConfig))
+//	dbconf := Watch[DBConfig](nil, "db")
+//	conf := dbconf.Load() // Get the consistent view of "db.host" and "db.port".
+//
+// If c is nil, use Conf instead. If group is empty, watch the options
+// without the group.
+//
+// Unlike reading the options one by one, the struct is rebuilt once
+// after all the values of a batch, such as LoadMap or LoadDataSet,
+// are applied, so the reader never sees a half-applied batch.
+//
+// The watcher should be closed by Close when it is no longer used.
+//
+// Notice: it will panic if T is not a struct, or any option does not exist
+// or its type does not match.
+func Watch[T any](c *Config, group string) *Watcher[T] {
+	if c == nil {
+		c = Conf
+	}
+
+	if t := reflect.TypeOf((*T)(nil)).Elem(); t.Kind() != reflect.Struct {
+		panic(fmt.Errorf("Watch: %s is not a struct", t))
+	}
+
+	w := &Watcher[T]{conf: c, prefix: c.Group(group).prefix}
+	w.cancel = c.addObservers(observer{hook: w.hook})
+	w.build(true)
+	return w
+}
+
+// Load returns the latest snapshot of the options,
+// which is immutable and must not be modified.
+func (w *Watcher[T]) Load() *T { return w.value.Load() }
+
+// Close stops rebuilding the snapshot when the options are changed,
+// and Load always returns the last snapshot after that.
+func (w *Watcher[T]) Close() { w.cancel() }
+
+func (w *Watcher[T]) hook(changes []optChange) {
+	for _, change := range changes {
+		if strings.HasPrefix(change.option.Opt().Name, w.prefix) {
+			w.build(false)
+			return
+		}
+	}
+}
+
+func (w *Watcher[T]) build(strict bool) {
+	value := new(T)
+	fields := w.conf.getStructFields(w.prefix, reflect.ValueOf(value).Elem())
+	options := make(map[string]*option, len(fields))
+	for name := range fields {
+		if option, ok := w.conf.getOption(name); ok {
+			options[name] = option
+		} else if strict {
+			panic(fmt.Errorf("no option named '%s'", name))
+		}
+	}
+
+	// Serialize the rebuilds so that the older snapshot
+	// never overrides the newer one.
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.conf.loadValues(func() {
+		for name, option := range options {
+			setStructField(name, fields[name], option.Get())
+		}
+	})
+	w.value.Store(value)
+}
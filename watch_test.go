@@ -0,0 +1,127 @@
+// Copyright 2026 xgfone
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gconf
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type watchDBConfig struct {
+	Host string `default:"127.0.0.1"`
+	Port int    `default:"3306"`
+}
+
+func TestWatch(t *testing.T) {
+	c := New()
+	c.Group("db").RegisterStruct(new(watchDBConfig))
+	c.RegisterOpts(StrOpt("name", ""))
+
+	w := Watch[watchDBConfig](c, "db")
+	if v := w.Load(); v.Host != "127.0.0.1" || v.Port != 3306 {
+		t.Errorf("unexpected the initial snapshot: %+v", *v)
+	}
+
+	// The options out of the group do not rebuild the snapshot.
+	old := w.Load()
+	_ = c.Set("name", "abc")
+	if w.Load() != old {
+		t.Errorf("unexpected the rebuilt snapshot")
+	}
+
+	_ = c.LoadMap(map[string]interface{}{"db.host": "1.2.3.4", "db.port": 3307})
+	if v := w.Load(); v.Host != "1.2.3.4" || v.Port != 3307 {
+		t.Errorf("unexpected the snapshot: %+v", *v)
+	} else if old.Host != "127.0.0.1" || old.Port != 3306 {
+		t.Errorf("the old snapshot is modified: %+v", *old)
+	}
+
+	c.Group("db").Reset()
+	if v := w.Load(); v.Host != "127.0.0.1" || v.Port != 3306 {
+		t.Errorf("unexpected the reset snapshot: %+v", *v)
+	}
+
+	// The closed watcher does not rebuild the snapshot any more.
+	w.Close()
+	if n := len(c.registry().hooks); n != 0 {
+		t.Errorf("expect no hooks, but got %d", n)
+	}
+
+	old = w.Load()
+	_ = c.Set("db.port", 3307)
+	if w.Load() != old {
+		t.Errorf("unexpected the rebuilt snapshot after closed")
+	}
+}
+
+func TestWatchConsistency(t *testing.T) {
+	c := New()
+	c.Group("db").RegisterStruct(new(watchDBConfig))
+	w := Watch[watchDBConfig](c, "db")
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				v := w.Load()
+				if v.Host != "127.0.0.1" && v.Host != strconv.Itoa(v.Port) {
+					t.Errorf("inconsistent snapshot: %+v", *v)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 1; i <= 100; i++ {
+		_ = c.LoadMap(map[string]interface{}{"db.host": strconv.Itoa(i), "db.port": i}, true)
+	}
+	close(stop)
+	wg.Wait()
+
+	if v := w.Load(); v.Host != "100" || v.Port != 100 {
+		t.Errorf("unexpected the last snapshot: %+v", *v)
+	}
+}
+
+func ExampleWatch() {
+	type DBConfig struct {
+		Host string `default:"127.0.0.1"`
+		Port int    `default:"3306"`
+	}
+
+	conf := New()
+	conf.Group("db").RegisterStruct(new(DBConfig))
+	dbconf := Watch[DBConfig](conf, "db")
+	defer dbconf.Close()
+	fmt.Printf("%+v\n", *dbconf.Load())
+
+	_ = conf.LoadMap(map[string]interface{}{"db.host": "192.168.1.10", "db.port": 3307})
+	fmt.Printf("%+v\n", *dbconf.Load())
+
+	// Output:
+	// {Host:127.0.0.1 Port:3306}
+	// {Host:192.168.1.10 Port:3307}
+}